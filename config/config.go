@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 type Config struct {
@@ -18,6 +19,13 @@ type Config struct {
 	Paragraph   ParagraphConfig   `json:"paragraph"`
 	Logging     LoggingConfig     `json:"logging"`
 	Language    LanguageConfig    `json:"language"`
+	Taxonomies  map[string]string `json:"taxonomies"` // Hugo 分类法，单数 → 复数
+	// TaxonomySettings 按分类法单数名覆盖词条页面目录、缓存、翻译策略与模板
+	TaxonomySettings map[string]TaxonomySettings `json:"taxonomy_settings"`
+	TimeZone         string                      `json:"time_zone"` // IANA 时区，解释不带时区的日期；留空使用本地时区
+	Filter           FilterConfig                `json:"filter"`
+	FrontMatter      FrontMatterConfig           `json:"front_matter"`
+	Slug             SlugConfig                  `json:"slug"`
 }
 
 // LLMConfig 描述一个可选择的模型服务。api_key 用于本地未跟踪配置；
@@ -55,7 +63,13 @@ type DisplayConfig struct {
 type PathsConfig struct {
 	DefaultContentDir string `json:"default_content_dir"`
 	TagsDir           string `json:"tags_dir"`
+	CategoriesDir     string `json:"categories_dir"` // 留空时为 tags_dir 的同级目录 categories
 	RuntimeDir        string `json:"runtime_dir"`
+	// ContentLayout 为 auto、filename 或 directory；directory 对应 Hugo 按语言设置 contentDir 的站点
+	ContentLayout       string            `json:"content_layout"`
+	SourceContentRoot   string            `json:"source_content_root"`   // directory 布局下源语言的内容根目录，留空时自动推断
+	LanguageContentDirs map[string]string `json:"language_content_dirs"` // directory 布局下各目标语言的内容根目录
+	HugoSiteRoot        string            `json:"hugo_site_root"`        // Hugo 站点根目录，设置后从站点配置推导语言、内容目录与分类法
 }
 
 type TranslationConfig struct {
@@ -78,9 +92,22 @@ type LoggingConfig struct {
 	File  string `json:"file"`
 }
 
+// FilterConfig 是各项操作默认使用的文章筛选条件，菜单中可在本次运行内修改。
+type FilterConfig struct {
+	Drafts     string   `json:"drafts"`    // 留空不筛选，only 只处理草稿，exclude 排除草稿
+	DateFrom   string   `json:"date_from"` // 含当天
+	DateTo     string   `json:"date_to"`   // 只写日期时包含当天
+	Paths      []string `json:"paths"`     // 相对内容目录的路径通配，支持 **
+	Tags       []string `json:"tags"`
+	Categories []string `json:"categories"`
+	Featured   *bool    `json:"featured"`
+}
+
 type LanguageConfig struct {
-	TargetLanguages []string          `json:"target_languages"`
-	LanguageNames   map[string]string `json:"language_names"`
+	SourceLanguage  string                  `json:"source_language"` // 源文件语言，对应 Hugo 的 defaultContentLanguage
+	TargetLanguages []string                `json:"target_languages"`
+	LanguageNames   map[string]string       `json:"language_names"` // 兼容旧配置，仅覆盖语言名称
+	Languages       map[string]LanguageSpec `json:"languages"`      // 新增或覆盖语言定义，见 languages.go
 }
 
 var defaultConfig = Config{
@@ -106,7 +133,9 @@ var defaultConfig = Config{
 	Paths: PathsConfig{
 		DefaultContentDir: "../../content/post",
 		TagsDir:           "../tags",
+		CategoriesDir:     "../categories",
 		RuntimeDir:        ".hugo-content-suite",
+		ContentLayout:     "auto",
 	}, Translation: TranslationConfig{
 		RetryAttempts:  2,
 		DelayBetweenMs: 0,
@@ -141,6 +170,8 @@ var defaultConfig = Config{
 			"hi": "Hindi",
 		},
 	},
+	Taxonomies: map[string]string{"tag": "tags", "category": "categories"},
+	Slug:       SlugConfig{Engine: SlugEngineLLM, PinyinFallback: true, Candidates: 3, Lock: SlugLockPublished, MaxWords: 8, MaxLength: 60},
 }
 
 func (c *Config) SelectedModel() (LLMConfig, error) {
@@ -193,6 +224,10 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("读取配置文件 %s 失败: %w", configPath, err)
 	}
+	var local map[string]interface{}
+	if err := json.Unmarshal(data, &local); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %v", err)
+	}
 	if filepath.Base(configPath) == "config.local.json" {
 		examplePath := filepath.Join(filepath.Dir(configPath), "config.example.json")
 		exampleData, err := os.ReadFile(examplePath)
@@ -210,9 +245,38 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("解析配置文件失败: %v", err)
 	}
 
+	if config.Paths.HugoSiteRoot != "" {
+		// 站点配置只填充 config.local.json 未显式设置的字段，示例配置中的默认值不算显式设置。
+		root := config.Paths.HugoSiteRoot
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(filepath.Dir(configPath), root)
+		}
+		site, err := LoadHugoSite(filepath.Clean(root))
+		if err != nil {
+			return nil, err
+		}
+		config.applyHugoSite(site, func(path string) bool { return hasJSONPath(local, path) })
+	}
+
 	if err := config.ResolvePaths(filepath.Dir(configPath)); err != nil {
 		return nil, err
 	}
+	if err := config.Language.Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.validateTaxonomies(); err != nil {
+		return nil, err
+	}
+	config.applyTaxonomies()
+	if err := config.FrontMatter.Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.Slug.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := time.LoadLocation(config.TimeZone); err != nil {
+		return nil, fmt.Errorf("time_zone %q 无效: %w", config.TimeZone, err)
+	}
 	return &config, nil
 }
 
@@ -260,9 +324,35 @@ func (c *Config) ResolvePaths(baseDir string) error {
 	if c.Paths.TagsDir, err = resolve(c.Paths.TagsDir); err != nil {
 		return err
 	}
+	if c.Paths.CategoriesDir, err = resolve(c.Paths.CategoriesDir); err != nil {
+		return err
+	}
+	if c.Paths.CategoriesDir == "" && c.Paths.TagsDir != "" {
+		c.Paths.CategoriesDir = filepath.Join(filepath.Dir(c.Paths.TagsDir), "categories")
+	}
 	if c.Paths.RuntimeDir, err = resolve(c.Paths.RuntimeDir); err != nil {
 		return err
 	}
+	if c.Paths.HugoSiteRoot, err = resolve(c.Paths.HugoSiteRoot); err != nil {
+		return err
+	}
+	if c.Paths.SourceContentRoot, err = resolve(c.Paths.SourceContentRoot); err != nil {
+		return err
+	}
+	for singular, settings := range c.TaxonomySettings {
+		if settings.Dir, err = resolve(settings.Dir); err != nil {
+			return err
+		}
+		if settings.Template, err = resolve(settings.Template); err != nil {
+			return err
+		}
+		c.TaxonomySettings[singular] = settings
+	}
+	for lang, dir := range c.Paths.LanguageContentDirs {
+		if c.Paths.LanguageContentDirs[lang], err = resolve(dir); err != nil {
+			return err
+		}
+	}
 	if c.Paths.RuntimeDir == "" {
 		return fmt.Errorf("paths.runtime_dir 不能为空")
 	}
@@ -279,10 +369,25 @@ func (c *Config) ResolvePaths(baseDir string) error {
 	return nil
 }
 
+// Location 返回解释日期使用的时区。
+func (c *Config) Location() *time.Location {
+	if c.TimeZone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
 // GetGlobalConfig 获取全局配置实例
 var globalConfig *Config
 
-func SetGlobalConfig(cfg *Config) { globalConfig = cfg }
+func SetGlobalConfig(cfg *Config) {
+	cfg.applyTaxonomies()
+	globalConfig = cfg
+}
 
 func GetGlobalConfig() *Config {
 	if globalConfig == nil {
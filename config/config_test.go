@@ -71,3 +71,201 @@ func TestLoadLocalConfigMergesExample(t *testing.T) {
 		t.Fatalf("示例路径未保留: %s", cfg.Paths.RuntimeDir)
 	}
 }
+
+func TestLoadConfigRejectsUnknownTargetLanguage(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	content := `{"language":{"target_languages":["en","xx"]}}`
+	if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(configPath); err == nil {
+		t.Fatal("未注册的目标语言应在加载配置时报错")
+	}
+}
+
+func TestLanguageRegistryOverrides(t *testing.T) {
+	joiner := "-"
+	cfg := LanguageConfig{
+		TargetLanguages: []string{"de", "zh-tw", "sw"},
+		LanguageNames:   map[string]string{"de": "Deutsch"},
+		Languages: map[string]LanguageSpec{
+			"zh-tw": {FileCode: "zh-hant"},
+			"sw":    {Name: "Swahili", Script: "latin", WordJoiner: &joiner, PromptTemplate: "Translate to {language}: {content}"},
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if name := cfg.DisplayName("de"); name != "Deutsch" {
+		t.Fatalf("language_names 未覆盖名称: %q", name)
+	}
+	if spec, _ := cfg.Spec("zh-tw"); spec.FileCode != "zh-hant" || spec.Joiner() != "" || !spec.MatchesScript("機器學習") {
+		t.Fatalf("zh-tw 定义错误: %#v", spec)
+	}
+	spec, _ := cfg.Spec("sw")
+	if spec.Joiner() != "-" || spec.Prompt("你好") != "Translate to Swahili: 你好" || spec.MatchesScript("你好") {
+		t.Fatalf("自定义语言定义错误: %#v", spec)
+	}
+
+	cfg.Languages["de"] = LanguageSpec{FileCode: "zh-hant"}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("重复的文件代码应报错")
+	}
+}
+
+func TestFrontMatterFieldsDerivePolicies(t *testing.T) {
+	cfg := FrontMatterConfig{
+		TranslatableFields: []TranslatableField{{Path: "title"}, {Path: "image.caption", Cache: FieldCacheNone}},
+		FieldPolicies:      map[string]string{"cover": FieldKeepLocal},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Field("image.caption"); !ok {
+		t.Fatal("应找到嵌套字段")
+	}
+	if _, ok := cfg.Field("summary"); ok {
+		t.Fatal("配置列表应替代内置列表")
+	}
+	for key, want := range map[string]string{"image": FieldTranslate, "cover": FieldKeepLocal, "aliases": FieldKeepLocal, "date": FieldCopy, "translationSourceHash": FieldKeepLocal} {
+		if got := cfg.Policy(key); got != want {
+			t.Fatalf("%s 的策略为 %s，应为 %s", key, got, want)
+		}
+	}
+	if _, ok := (FrontMatterConfig{}).Field("subtitle"); !ok {
+		t.Fatal("内置列表应包含 subtitle")
+	}
+
+	cfg.TranslatableFields[1].Cache = "slug"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("未知的缓存类型应报错")
+	}
+}
+
+func TestLoadConfigDerivesHugoSiteSettings(t *testing.T) {
+	dir := t.TempDir()
+	site := filepath.Join(dir, "site")
+	if err := os.MkdirAll(filepath.Join(site, "config", "_default"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	hugo := "defaultContentLanguage = \"zh-cn\"\n[taxonomies]\ntag = \"labels\"\nseries = \"series\"\n[params]\nmainSections = [\"notes\"]\n"
+	languages := "zh-cn:\n  weight: 1\n  contentDir: content/zh\nja:\n  weight: 3\n  contentDir: content/ja\nen:\n  weight: 2\n  contentDir: content/en\nde:\n  disabled: true\nsw:\n  weight: 4\n  languageName: Kiswahili\n  contentDir: content/sw\n"
+	if err := os.WriteFile(filepath.Join(site, "hugo.toml"), []byte(hugo), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(site, "config", "_default", "languages.yaml"), []byte(languages), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	example := `{"paths":{"default_content_dir":"content/post","tags_dir":"content/tags","runtime_dir":"runtime","hugo_site_root":"site"},"language":{"target_languages":["en"]},"taxonomies":{"tag":"tags"}}`
+	local := `{"paths":{"tags_dir":"custom-tags"}}`
+	if err := os.WriteFile(filepath.Join(dir, "config.example.json"), []byte(example), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.local.json"), []byte(local), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(filepath.Join(dir, "config.local.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Language.TargetLanguages; len(got) != 3 || got[0] != "en" || got[1] != "ja" || got[2] != "sw" {
+		t.Fatalf("目标语言应按 weight 从 Hugo 推导: %v", got)
+	}
+	if cfg.Language.SourceLanguage != "zh-cn" || cfg.Language.DisplayName("sw") != "Kiswahili" {
+		t.Fatalf("源语言或语言名称错误: %q %q", cfg.Language.SourceLanguage, cfg.Language.DisplayName("sw"))
+	}
+	if cfg.Paths.DefaultContentDir != filepath.Join(site, "content", "zh", "notes") || cfg.Paths.SourceContentRoot != filepath.Join(site, "content", "zh") {
+		t.Fatalf("内容目录错误: %s %s", cfg.Paths.DefaultContentDir, cfg.Paths.SourceContentRoot)
+	}
+	if cfg.Paths.LanguageContentDirs["ja"] != filepath.Join(site, "content", "ja") {
+		t.Fatalf("语言目录错误: %v", cfg.Paths.LanguageContentDirs)
+	}
+	if cfg.Paths.TagsDir != filepath.Join(dir, "custom-tags") {
+		t.Fatalf("config.local.json 中的显式设置应优先: %s", cfg.Paths.TagsDir)
+	}
+	if cfg.Paths.CategoriesDir != filepath.Join(site, "content", "zh", "categories") {
+		t.Fatalf("分类目录应从 Hugo 推导: %s", cfg.Paths.CategoriesDir)
+	}
+	if cfg.Taxonomies["tag"] != "labels" || cfg.Taxonomies["series"] != "series" {
+		t.Fatalf("分类法未从 Hugo 推导: %v", cfg.Taxonomies)
+	}
+}
+
+func TestTaxonomiesDeriveDirsFieldsAndPolicies(t *testing.T) {
+	cfg := &Config{
+		Paths:      PathsConfig{TagsDir: filepath.Join("site", "content", "tags"), CategoriesDir: filepath.Join("site", "content", "categories")},
+		Taxonomies: map[string]string{"tag": "tags", "category": "categories", "series": "series", "author": "authors"},
+		TaxonomySettings: map[string]TaxonomySettings{
+			"author": {Policy: FieldCopy},
+			"series": {Cache: "series_terms"},
+		},
+	}
+	if err := cfg.validateTaxonomies(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.applyTaxonomies()
+
+	list := cfg.TaxonomyList()
+	if len(list) != 4 || list[0].Singular != "tag" || list[1].Singular != "category" || list[2].Singular != "author" || list[3].Singular != "series" {
+		t.Fatalf("分类法顺序错误: %#v", list)
+	}
+	series, _ := cfg.Taxonomy("series")
+	if series.Dir != filepath.Join("site", "content", "series") || series.Cache != "series_terms" {
+		t.Fatalf("series 的目录或缓存错误: %#v", series)
+	}
+	if field, ok := cfg.FrontMatter.Field("series"); !ok || field.Cache != "series_terms" {
+		t.Fatalf("series 应成为可翻译字段: %#v", field)
+	}
+	if _, ok := cfg.FrontMatter.Field("authors"); ok || cfg.FrontMatter.Policy("authors") != FieldCopy {
+		t.Fatal("copy 策略的分类法不应翻译")
+	}
+	if field, ok := cfg.FrontMatter.Field("tags"); !ok || field.Cache != FieldCacheTag {
+		t.Fatalf("标签字段应使用标签缓存: %#v", field)
+	}
+
+	cfg.FrontMatter.TranslatableFields = []TranslatableField{{Path: "title"}, {Path: "series", Cache: "series_terms"}}
+	if err := cfg.FrontMatter.Validate(); err != nil {
+		t.Fatalf("分类法的缓存命名空间应可用于字段: %v", err)
+	}
+	cfg.TaxonomySettings["series"] = TaxonomySettings{Cache: "article"}
+	if err := cfg.validateTaxonomies(); err == nil {
+		t.Fatal("不应与 slug 缓存共用命名空间")
+	}
+	cfg.TaxonomySettings = map[string]TaxonomySettings{"topic": {}}
+	if err := cfg.validateTaxonomies(); err == nil {
+		t.Fatal("未配置的分类法应报错")
+	}
+}
+
+func TestSlugPolicyNormalizesDeterministically(t *testing.T) {
+	policy := SlugConfig{MaxWords: 5, MaxLength: 30, Acronyms: []string{"k8s", "ci"}}
+	for slug, want := range map[string]string{
+		"a-guide-to-the-go-scheduler": "guide-go-scheduler",
+		"deploying-to-k-8-s-with-ci":  "deploying-k8s-ci",
+		"the-of":                      "the-of",
+		"understanding-distributed-consensus-raft-paxos-zab": "understanding-distributed",
+		"supercalifragilisticexpialidocious-words":           "supercalifragilisticexpialidoc",
+	} {
+		got := policy.Apply(slug, "en")
+		if got != want {
+			t.Errorf("Apply(%q)=%q want %q", slug, got, want)
+		}
+		if policy.Apply(got, "en") != got {
+			t.Errorf("Apply(%q) 不是幂等的", got)
+		}
+		if problems := policy.Violations(got, "en"); len(problems) > 0 && got != "the-of" {
+			t.Errorf("整理后的 %q 仍不符合规范: %v", got, problems)
+		}
+	}
+	if problems := policy.Violations("deploying-to-k-8-s", "en"); len(problems) != 2 {
+		t.Errorf("应报告虚词与被拆开的缩写: %v", problems)
+	}
+	if (SlugConfig{Stopwords: map[string][]string{"en": {}}}).Apply("the-go-book", "en") != "the-go-book" {
+		t.Error("配置为空列表时应关闭虚词")
+	}
+	if (SlugConfig{Acronyms: []string{"K8s"}}).Validate() == nil {
+		t.Error("缩写必须是小写字母与数字")
+	}
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 前置数据字段在源文件与译文之间的同步策略。
+const (
+	FieldCopy      = "copy"       // 直接使用源文件的值，如 date、draft、slug、图片路径
+	FieldTranslate = "translate"  // 翻译源文件的值，源文本变化时才重新翻译
+	FieldKeepLocal = "keep-local" // 译文自行维护，同步与重新翻译都保留译文中的值
+)
+
+// 前置数据字段翻译使用的缓存。标签、分类这类短而重复的值适合缓存，标题、摘要默认不缓存；
+// 其他分类法使用各自的缓存命名空间，见 TaxonomySettings。
+const (
+	FieldCacheNone     = "none"
+	FieldCacheTag      = "tag"
+	FieldCacheCategory = "category"
+)
+
+// TranslatableField 描述一个需要翻译的前置数据字段。
+type TranslatableField struct {
+	Path           string `json:"path"`            // 点分路径，如 title、image.caption；数组逐个翻译元素
+	Cache          string `json:"cache"`           // none（默认）或分类法的缓存命名空间，如 tag、category
+	PromptTemplate string `json:"prompt_template"` // 用户消息模板，占位符同 language.languages；留空使用语言的模板
+}
+
+// FrontMatterConfig 描述前置数据字段的翻译与同步。translatable_fields 列出需要翻译的字段，
+// 为空时使用内置列表；分类法对应的字段由 taxonomies 推导，无需列出。field_policies 按顶层键设置
+// 同步策略，包含可翻译字段的顶层键默认为 translate，其余未列出的字段使用 default_policy，留空时为 copy。
+type FrontMatterConfig struct {
+	TranslatableFields []TranslatableField `json:"translatable_fields"`
+	FieldPolicies      map[string]string   `json:"field_policies"`
+	DefaultPolicy      string              `json:"default_policy"`
+
+	// 由分类法推导，见 Config.applyTaxonomies；未推导时使用 tags、categories 的内置字段
+	taxonomyFields   []TranslatableField
+	taxonomyPolicies map[string]string
+}
+
+const (
+	titlePromptTemplate   = "请将以下文章标题翻译为 {language}，保持标题简洁，只输出译文: {content}"
+	summaryPromptTemplate = "请将以下文章摘要翻译为 {language}，语句自然通顺，只输出译文: {content}"
+)
+
+// defaultTranslatableFields 对应 models.Article 解析的可翻译字段。
+var defaultTranslatableFields = []TranslatableField{
+	{Path: "title", PromptTemplate: titlePromptTemplate},
+	{Path: "subtitle", PromptTemplate: titlePromptTemplate},
+	{Path: "description", PromptTemplate: summaryPromptTemplate},
+	{Path: "summary", PromptTemplate: summaryPromptTemplate},
+}
+
+// defaultTaxonomyFields 对应 Hugo 默认的 tags 与 categories 分类法。
+var defaultTaxonomyFields = []TranslatableField{
+	{Path: "tags", Cache: FieldCacheTag},
+	{Path: "categories", Cache: FieldCacheCategory},
+}
+
+// defaultFieldPolicies 在配置未覆盖时生效。aliases 与 url 是各语言页面自己的地址，
+// 复制源文件的值会让不同语言的页面争用同一地址。
+var defaultFieldPolicies = map[string]string{
+	"aliases": FieldKeepLocal,
+	"url":     FieldKeepLocal,
+}
+
+// Fields 返回需要翻译的字段：配置或内置的字段在前，随后是未被列出的分类法字段。
+func (c FrontMatterConfig) Fields() []TranslatableField {
+	fields := c.TranslatableFields
+	if len(fields) == 0 {
+		fields = defaultTranslatableFields
+	}
+	taxonomyFields := c.taxonomyFields
+	if c.taxonomyPolicies == nil {
+		taxonomyFields = defaultTaxonomyFields
+	}
+	result := append([]TranslatableField(nil), fields...)
+	for _, field := range taxonomyFields {
+		if !containsFieldPath(fields, field.Path) {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+func containsFieldPath(fields []TranslatableField, path string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(field.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Field 按点分路径查找可翻译字段，路径不区分大小写。
+func (c FrontMatterConfig) Field(path string) (TranslatableField, bool) {
+	for _, field := range c.Fields() {
+		if strings.EqualFold(field.Path, path) {
+			return field, true
+		}
+	}
+	return TranslatableField{}, false
+}
+
+// translationSourceHashKey 由翻译流程维护，不参与字段同步；与 models.TranslationSourceHashKey 一致。
+const translationSourceHashKey = "translationSourceHash"
+
+// Policy 返回顶层字段 key 的同步策略，键名不区分大小写。
+func (c FrontMatterConfig) Policy(key string) string {
+	if strings.EqualFold(key, translationSourceHashKey) {
+		return FieldKeepLocal
+	}
+	for name, policy := range c.FieldPolicies {
+		if strings.EqualFold(name, key) {
+			return policy
+		}
+	}
+	if policy, ok := c.taxonomyPolicies[strings.ToLower(key)]; ok {
+		return policy
+	}
+	for _, field := range c.Fields() {
+		root, _, _ := strings.Cut(field.Path, ".")
+		if strings.EqualFold(root, key) {
+			return FieldTranslate
+		}
+	}
+	if policy, ok := defaultFieldPolicies[strings.ToLower(key)]; ok {
+		return policy
+	}
+	if c.DefaultPolicy != "" {
+		return c.DefaultPolicy
+	}
+	return FieldCopy
+}
+
+// Validate 检查可翻译字段与策略名称。字段的缓存只能是 none 或分类法的缓存命名空间。
+func (c FrontMatterConfig) Validate() error {
+	caches := map[string]bool{"": true, FieldCacheNone: true, FieldCacheTag: true, FieldCacheCategory: true}
+	for _, field := range c.taxonomyFields {
+		caches[field.Cache] = true
+	}
+	for i, field := range c.TranslatableFields {
+		if strings.TrimSpace(field.Path) == "" {
+			return fmt.Errorf("front_matter.translatable_fields[%d] 缺少 path", i)
+		}
+		if !caches[field.Cache] {
+			return fmt.Errorf("front_matter.translatable_fields[%d] 的缓存 %q 无效，应为 none 或分类法的缓存命名空间", i, field.Cache)
+		}
+	}
+	for name, policy := range c.FieldPolicies {
+		if !validFieldPolicy(policy) {
+			return fmt.Errorf("front_matter.field_policies.%s 的策略 %q 无效，应为 copy、translate 或 keep-local", name, policy)
+		}
+	}
+	if c.DefaultPolicy != "" && !validFieldPolicy(c.DefaultPolicy) {
+		return fmt.Errorf("front_matter.default_policy %q 无效，应为 copy、translate 或 keep-local", c.DefaultPolicy)
+	}
+	return nil
+}
+
+func validFieldPolicy(policy string) bool {
+	return policy == FieldCopy || policy == FieldTranslate || policy == FieldKeepLocal
+}
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// HugoSite 是从 Hugo 站点配置推导出的语言、内容目录与分类法信息，路径均为绝对路径。
+type HugoSite struct {
+	Root                   string
+	DefaultContentLanguage string
+	TargetLanguages        []string          // 除默认语言外、未禁用的语言，按 weight 排序
+	LanguageNames          map[string]string // languageName，未设置的语言不出现
+	ContentDir             string            // 默认语言的内容目录
+	LanguageContentDirs    map[string]string // 单独设置了 contentDir 的目标语言
+	Taxonomies             map[string]string // 单数 → 复数，如 tag → tags
+	MainSections           []string          // params.mainSections
+	TimeZone               string
+}
+
+var hugoConfigExtensions = []string{".toml", ".yaml", ".yml", ".json"}
+
+// LoadHugoSite 读取站点根目录下的 hugo.* 或 config.*，以及 config/_default/ 中的配置文件。
+// Hugo 的键不区分大小写，这里统一转为小写后再读取。
+func LoadHugoSite(root string) (*HugoSite, error) {
+	merged := make(map[string]interface{})
+	found := false
+
+	for _, name := range []string{"hugo", "config"} {
+		path, ok := findHugoConfigFile(filepath.Join(root, name))
+		if !ok {
+			continue
+		}
+		data, err := readHugoConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		mergeJSONObject(merged, data)
+		found = true
+		break
+	}
+
+	defaultDir := filepath.Join(root, "config", "_default")
+	entries, err := os.ReadDir(defaultDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || !isHugoConfigExtension(ext) {
+			continue
+		}
+		stem := strings.ToLower(strings.TrimSuffix(entry.Name(), ext))
+		// params.en.toml、menus.zh.yaml 等按语言拆分的文件与本工具无关。
+		if strings.Contains(stem, ".") {
+			continue
+		}
+		data, err := readHugoConfigFile(filepath.Join(defaultDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if stem == "hugo" || stem == "config" {
+			mergeJSONObject(merged, data)
+		} else {
+			mergeJSONObject(merged, map[string]interface{}{stem: data})
+		}
+		found = true
+	}
+	if !found {
+		return nil, fmt.Errorf("在 %s 中找不到 Hugo 配置文件（hugo.toml、config.toml 或 config/_default/）", root)
+	}
+	return newHugoSite(root, merged), nil
+}
+
+func findHugoConfigFile(base string) (string, bool) {
+	for _, ext := range hugoConfigExtensions {
+		if info, err := os.Stat(base + ext); err == nil && !info.IsDir() {
+			return base + ext, true
+		}
+	}
+	return "", false
+}
+
+func isHugoConfigExtension(ext string) bool {
+	for _, candidate := range hugoConfigExtensions {
+		if strings.EqualFold(ext, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func readHugoConfigFile(path string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 Hugo 配置 %s 失败: %w", path, err)
+	}
+	data := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		_, err = toml.Decode(string(content), &data)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &data)
+	case ".json":
+		err = json.Unmarshal(content, &data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析 Hugo 配置 %s 失败: %w", path, err)
+	}
+	return lowerKeys(data), nil
+}
+
+func lowerKeys(data map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		if child, ok := value.(map[string]interface{}); ok {
+			value = lowerKeys(child)
+		}
+		result[strings.ToLower(key)] = value
+	}
+	return result
+}
+
+func newHugoSite(root string, data map[string]interface{}) *HugoSite {
+	site := &HugoSite{
+		Root:                   root,
+		DefaultContentLanguage: "en",
+		LanguageNames:          make(map[string]string),
+		LanguageContentDirs:    make(map[string]string),
+		Taxonomies:             map[string]string{"tag": "tags", "category": "categories"},
+	}
+	if lang, ok := data["defaultcontentlanguage"].(string); ok && lang != "" {
+		site.DefaultContentLanguage = strings.ToLower(lang)
+	}
+	if zone, ok := data["timezone"].(string); ok {
+		site.TimeZone = zone
+	}
+	contentDir := "content"
+	if dir, ok := data["contentdir"].(string); ok && dir != "" {
+		contentDir = dir
+	}
+	site.ContentDir = filepath.Join(root, contentDir)
+
+	if taxonomies, ok := data["taxonomies"].(map[string]interface{}); ok {
+		site.Taxonomies = make(map[string]string)
+		for singular, plural := range taxonomies {
+			if name, ok := plural.(string); ok && name != "" {
+				site.Taxonomies[singular] = name
+			}
+		}
+	}
+
+	if params, ok := data["params"].(map[string]interface{}); ok {
+		sections, _ := params["mainsections"].([]interface{})
+		for _, section := range sections {
+			if name, ok := section.(string); ok && name != "" {
+				site.MainSections = append(site.MainSections, name)
+			}
+		}
+	}
+
+	type weighted struct {
+		code   string
+		weight int
+	}
+	var targets []weighted
+	languages, _ := data["languages"].(map[string]interface{})
+	for code, raw := range languages {
+		settings, _ := raw.(map[string]interface{})
+		if disabled, _ := settings["disabled"].(bool); disabled {
+			continue
+		}
+		if name, ok := settings["languagename"].(string); ok && name != "" {
+			site.LanguageNames[code] = name
+		}
+		dir, _ := settings["contentdir"].(string)
+		if code == site.DefaultContentLanguage {
+			if dir != "" {
+				site.ContentDir = filepath.Join(root, dir)
+			}
+			continue
+		}
+		if dir != "" {
+			site.LanguageContentDirs[code] = filepath.Join(root, dir)
+		}
+		targets = append(targets, weighted{code: code, weight: intValue(settings["weight"])})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].weight != targets[j].weight {
+			return targets[i].weight < targets[j].weight
+		}
+		return targets[i].code < targets[j].code
+	})
+	for _, target := range targets {
+		site.TargetLanguages = append(site.TargetLanguages, target.code)
+	}
+	return site
+}
+
+func intValue(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// ArticleDir 返回文章所在目录：优先 params.mainSections 的第一项，其次常见的 post 目录，
+// 都没有时使用整个内容目录。
+func (s *HugoSite) ArticleDir() string {
+	if len(s.MainSections) > 0 {
+		return filepath.Join(s.ContentDir, s.MainSections[0])
+	}
+	for _, section := range []string{"post", "posts"} {
+		dir := filepath.Join(s.ContentDir, section)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return s.ContentDir
+}
+
+// TagsDir 返回 tag 分类法在默认语言内容目录下的页面目录。
+func (s *HugoSite) TagsDir() string {
+	return s.taxonomyDir("tag", "tags")
+}
+
+// CategoriesDir 返回 category 分类法在默认语言内容目录下的页面目录。
+func (s *HugoSite) CategoriesDir() string {
+	return s.taxonomyDir("category", "categories")
+}
+
+func (s *HugoSite) taxonomyDir(singular, fallback string) string {
+	plural, ok := s.Taxonomies[singular]
+	if !ok {
+		plural = fallback
+	}
+	return filepath.Join(s.ContentDir, plural)
+}
+
+// applyHugoSite 用站点配置填充未在本地配置中显式设置的字段。explicit 判断某个
+// 点分路径（如 paths.tags_dir）是否由用户写在配置文件里。
+func (c *Config) applyHugoSite(site *HugoSite, explicit func(path string) bool) {
+	if !explicit("language.target_languages") && len(site.TargetLanguages) > 0 {
+		c.Language.TargetLanguages = site.TargetLanguages
+	}
+	if !explicit("language.source_language") {
+		c.Language.SourceLanguage = site.DefaultContentLanguage
+	}
+	// Hugo 中声明的语言即视为已注册；内置定义仍提供书写系统与示例。
+	for code, name := range site.LanguageNames {
+		if _, builtin := builtinLanguages[code]; builtin {
+			continue
+		}
+		if c.Language.Languages == nil {
+			c.Language.Languages = make(map[string]LanguageSpec)
+		}
+		if spec, ok := c.Language.Languages[code]; !ok || spec.Name == "" {
+			spec.Name = name
+			c.Language.Languages[code] = spec
+		}
+	}
+	for _, code := range site.TargetLanguages {
+		if _, ok := c.Language.Spec(code); ok {
+			continue
+		}
+		if c.Language.Languages == nil {
+			c.Language.Languages = make(map[string]LanguageSpec)
+		}
+		c.Language.Languages[code] = LanguageSpec{}
+	}
+	if !explicit("paths.default_content_dir") {
+		c.Paths.DefaultContentDir = site.ArticleDir()
+	}
+	if !explicit("paths.tags_dir") {
+		c.Paths.TagsDir = site.TagsDir()
+	}
+	if !explicit("paths.categories_dir") {
+		c.Paths.CategoriesDir = site.CategoriesDir()
+	}
+	if len(site.LanguageContentDirs) > 0 {
+		if !explicit("paths.source_content_root") {
+			c.Paths.SourceContentRoot = site.ContentDir
+		}
+		if !explicit("paths.language_content_dirs") {
+			c.Paths.LanguageContentDirs = site.LanguageContentDirs
+		}
+	}
+	if !explicit("time_zone") && site.TimeZone != "" {
+		c.TimeZone = site.TimeZone
+	}
+	if !explicit("taxonomies") {
+		c.Taxonomies = site.Taxonomies
+	}
+}
+
+// hasJSONPath 判断 JSON 对象中是否存在点分路径对应的键。
+func hasJSONPath(data map[string]interface{}, path string) bool {
+	current := data
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		value, ok := current[part]
+		if !ok {
+			return false
+		}
+		if i == len(parts)-1 {
+			return true
+		}
+		if current, ok = value.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return false
+}
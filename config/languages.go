@@ -0,0 +1,192 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultPromptTemplate 是翻译请求的用户消息模板，{language} 与 {content} 为占位符。
+const DefaultPromptTemplate = "请将以下内容翻译为 {language}: {content}"
+
+// PromptExample 是提供给模型的一组示例翻译。
+type PromptExample struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// LanguageSpec 描述一种目标语言。内置语言可在 language.languages 中按字段覆盖，
+// 未内置的语言必须在其中声明后才能出现在 target_languages。
+type LanguageSpec struct {
+	Code           string          `json:"-"`
+	FileCode       string          `json:"file_code"`       // 译文文件名后缀或语言目录名，默认与语言代码相同
+	Name           string          `json:"name"`            // 提示词中使用的语言名称
+	Script         string          `json:"script"`          // 书写系统，用于校验译文，如 latin、cyrillic、japanese
+	WordJoiner     *string         `json:"word_joiner"`     // 合并拆分段落时的连接符；未设置时按书写系统推断
+	PromptTemplate string          `json:"prompt_template"` // 用户消息模板，留空使用 DefaultPromptTemplate
+	Examples       []PromptExample `json:"examples"`
+}
+
+// Joiner 返回合并拆分段落时使用的连接符：中日韩与泰文不以空格分词。
+func (s LanguageSpec) Joiner() string {
+	if s.WordJoiner != nil {
+		return *s.WordJoiner
+	}
+	switch s.Script {
+	case "han", "japanese", "thai":
+		return ""
+	default:
+		return " "
+	}
+}
+
+// Prompt 按模板生成翻译请求。
+func (s LanguageSpec) Prompt(content string) string {
+	return s.PromptWith("", content)
+}
+
+// PromptWith 用指定模板生成翻译请求，如前置数据字段各自的模板；template 为空时同 Prompt。
+func (s LanguageSpec) PromptWith(template, content string) string {
+	if template == "" {
+		template = s.PromptTemplate
+	}
+	if template == "" {
+		template = DefaultPromptTemplate
+	}
+	return strings.NewReplacer("{language}", s.Name, "{content}", content).Replace(template)
+}
+
+var scriptTables = map[string][]*unicode.RangeTable{
+	"latin":      {unicode.Latin},
+	"cyrillic":   {unicode.Cyrillic},
+	"han":        {unicode.Han},
+	"japanese":   {unicode.Han, unicode.Hiragana, unicode.Katakana},
+	"hangul":     {unicode.Hangul},
+	"devanagari": {unicode.Devanagari},
+	"arabic":     {unicode.Arabic},
+	"thai":       {unicode.Thai},
+}
+
+// MatchesScript 判断文本是否包含该语言书写系统的字符；未声明书写系统时总是通过。
+func (s LanguageSpec) MatchesScript(text string) bool {
+	tables, ok := scriptTables[s.Script]
+	if !ok {
+		return true
+	}
+	for _, r := range text {
+		if unicode.In(r, tables...) {
+			return true
+		}
+	}
+	return false
+}
+
+func commonExamples(ai, ml, list string) []PromptExample {
+	return []PromptExample{
+		{Source: "人工智能", Target: ai},
+		{Source: "机器学习", Target: ml},
+		{Source: "- 数据挖掘\n- 深度学习\n- 神经网络", Target: list},
+	}
+}
+
+var builtinLanguages = map[string]LanguageSpec{
+	"en":    {Name: "English", Script: "latin", Examples: commonExamples("Artificial Intelligence", "Machine Learning", "- Data Mining\n- Deep Learning\n- Neural Network")},
+	"ja":    {Name: "Japanese", Script: "japanese", Examples: commonExamples("人工知能", "機械学習", "- データマイニング\n- ディープラーニング\n- ニューラルネットワーク")},
+	"ko":    {Name: "Korean", Script: "hangul", Examples: commonExamples("인공지능", "기계학습", "- 데이터 마이닝\n- 딥러닝\n- 신경망")},
+	"fr":    {Name: "French", Script: "latin", Examples: commonExamples("Intelligence Artificielle", "Apprentissage Automatique", "- Exploration de Données\n- Apprentissage Profond\n- Réseau de Neurones")},
+	"ru":    {Name: "Russian", Script: "cyrillic", Examples: commonExamples("Искусственный интеллект", "Машинное обучение", "- Интеллектуальный анализ данных\n- Глубокое обучение\n- Нейронная сеть")},
+	"hi":    {Name: "Hindi", Script: "devanagari", Examples: commonExamples("कृत्रिम बुद्धिमत्ता", "मशीन लर्निंग", "- डेटा माइनिंग\n- डीप लर्निंग\n- न्यूरल नेटवर्क")},
+	"de":    {Name: "German", Script: "latin", Examples: commonExamples("Künstliche Intelligenz", "Maschinelles Lernen", "- Data-Mining\n- Deep Learning\n- Neuronales Netz")},
+	"es":    {Name: "Spanish", Script: "latin", Examples: commonExamples("Inteligencia Artificial", "Aprendizaje Automático", "- Minería de Datos\n- Aprendizaje Profundo\n- Red Neuronal")},
+	"pt":    {Name: "Portuguese", Script: "latin"},
+	"it":    {Name: "Italian", Script: "latin"},
+	"zh-tw": {Name: "Traditional Chinese (Taiwan)", Script: "han", Examples: commonExamples("人工智慧", "機器學習", "- 資料探勘\n- 深度學習\n- 神經網路")},
+}
+
+// Registry 合并内置语言、language_names 与 language.languages，返回按语言代码索引的注册表。
+func (c LanguageConfig) Registry() map[string]LanguageSpec {
+	registry := make(map[string]LanguageSpec, len(builtinLanguages))
+	for code, spec := range builtinLanguages {
+		registry[code] = spec
+	}
+	for code, name := range c.LanguageNames {
+		spec := registry[code]
+		spec.Name = name
+		registry[code] = spec
+	}
+	for code, override := range c.Languages {
+		spec := registry[code]
+		if override.FileCode != "" {
+			spec.FileCode = override.FileCode
+		}
+		if override.Name != "" {
+			spec.Name = override.Name
+		}
+		if override.Script != "" {
+			spec.Script = override.Script
+		}
+		if override.WordJoiner != nil {
+			spec.WordJoiner = override.WordJoiner
+		}
+		if override.PromptTemplate != "" {
+			spec.PromptTemplate = override.PromptTemplate
+		}
+		if len(override.Examples) > 0 {
+			spec.Examples = override.Examples
+		}
+		registry[code] = spec
+	}
+	for code, spec := range registry {
+		spec.Code = code
+		if spec.FileCode == "" {
+			spec.FileCode = code
+		}
+		if spec.Name == "" {
+			spec.Name = code
+		}
+		registry[code] = spec
+	}
+	return registry
+}
+
+// Spec 返回语言定义；未注册的语言按代码本身返回，供显示和兜底使用。
+func (c LanguageConfig) Spec(code string) (LanguageSpec, bool) {
+	spec, ok := c.Registry()[code]
+	if !ok {
+		return LanguageSpec{Code: code, FileCode: code, Name: code}, false
+	}
+	return spec, true
+}
+
+// DisplayName 返回语言名称，未注册时返回代码本身。
+func (c LanguageConfig) DisplayName(code string) string {
+	spec, _ := c.Spec(code)
+	return spec.Name
+}
+
+// Validate 拒绝未注册的目标语言与重复的文件代码：未知代码若被静默映射，
+// 会覆盖其他语言已有的译文。
+func (c LanguageConfig) Validate() error {
+	registry := c.Registry()
+	fileCodes := make(map[string]string)
+	for _, code := range c.TargetLanguages {
+		spec, ok := registry[code]
+		if !ok {
+			known := make([]string, 0, len(registry))
+			for name := range registry {
+				known = append(known, name)
+			}
+			sort.Strings(known)
+			return fmt.Errorf("未知目标语言 %q；请在 language.languages 中声明，已注册: %s", code, strings.Join(known, ", "))
+		}
+		if _, ok := scriptTables[spec.Script]; spec.Script != "" && !ok {
+			return fmt.Errorf("语言 %s 的 script %q 不受支持", code, spec.Script)
+		}
+		if other, exists := fileCodes[spec.FileCode]; exists {
+			return fmt.Errorf("语言 %s 与 %s 使用相同的文件代码 %q", other, code, spec.FileCode)
+		}
+		fileCodes[spec.FileCode] = code
+	}
+	return nil
+}
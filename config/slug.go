@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// slug 锁定策略
+const (
+	SlugLockPublished = "published" // 已发布文章的 slug 只在执行时确认强制修改后才会改变
+	SlugLockNone      = "none"      // 不锁定，按翻译结果更新所有文章的 slug
+)
+
+// slug 生成方式
+const (
+	SlugEngineLLM    = "llm"    // 由模型把标题、词条翻译为英文
+	SlugEnginePinyin = "pinyin" // 离线把汉字转写为拼音，不需要模型服务
+)
+
+// SlugConfig 控制文章与词条 slug 的生成与修改。
+type SlugConfig struct {
+	Engine         string              `json:"engine"`          // llm（默认）或 pinyin，菜单中可按次选择
+	PinyinFallback bool                `json:"pinyin_fallback"` // 模型服务不可用时改用拼音生成 slug
+	Candidates     int                 `json:"candidates"`      // 挑选文章 slug 时向模型请求的候选数，0 表示不请求
+	Lock           string              `json:"lock"`            // published（默认）或 none
+	MaxWords       int                 `json:"max_words"`       // slug 最多的单词数，0 表示不限
+	MaxLength      int                 `json:"max_length"`      // slug 最多的字符数，0 表示不限；超出时在单词边界截断
+	Stopwords      map[string][]string `json:"stopwords"`       // 按 slug 的语言去掉的虚词，如 en 的 a、the、of
+	Acronyms       []string            `json:"acronyms"`        // 保持完整的缩写，如 k8s、ci；不会被当作虚词去掉，也不会被拆开
+}
+
+// defaultSlugStopwords 是 stopwords 中没有配置该语言时使用的虚词，配置为空列表即可关闭
+var defaultSlugStopwords = map[string][]string{
+	"en": {"a", "an", "the", "of", "to", "for", "and", "or", "in", "on", "at", "by", "with", "from", "is", "are"},
+}
+
+// Validate 检查 slug 配置。
+func (s SlugConfig) Validate() error {
+	switch s.Engine {
+	case "", SlugEngineLLM, SlugEnginePinyin:
+	default:
+		return fmt.Errorf("slug.engine %q 无效，应为 llm 或 pinyin", s.Engine)
+	}
+	switch s.Lock {
+	case "", SlugLockPublished, SlugLockNone:
+	default:
+		return fmt.Errorf("slug.lock %q 无效，应为 published 或 none", s.Lock)
+	}
+	if s.MaxWords < 0 || s.MaxLength < 0 || s.Candidates < 0 {
+		return fmt.Errorf("slug.max_words、slug.max_length 与 slug.candidates 不能为负数")
+	}
+	for _, acronym := range s.Acronyms {
+		if acronym == "" || strings.ToLower(acronym) != acronym || strings.Trim(acronym, "abcdefghijklmnopqrstuvwxyz0123456789") != "" {
+			return fmt.Errorf("slug.acronyms 中的 %q 无效，只能包含小写字母与数字", acronym)
+		}
+	}
+	return nil
+}
+
+// LockPublished 报告是否锁定已发布文章的 slug。
+func (s SlugConfig) LockPublished() bool {
+	return s.Lock != SlugLockNone
+}
+
+// stopwords 返回语言 lang 的虚词集合
+func (s SlugConfig) stopwords(lang string) map[string]bool {
+	words, ok := s.Stopwords[lang]
+	if !ok {
+		words = defaultSlugStopwords[lang]
+	}
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[strings.ToLower(word)] = true
+	}
+	return set
+}
+
+// Apply 按 slug 规范整理已格式化的 slug（小写字母、数字与连字符），lang 是 slug 所用的语言：
+//  1. 被连字符拆开的缩写重新合并，如 k-8-s → k8s；
+//  2. 去掉虚词，缩写除外；全部是虚词时保留原样；
+//  3. 超过 max_words 的单词从末尾去掉，再按 max_length 在单词边界截断，
+//     第一个单词本身超长时才截断单词。
+//
+// 结果只由输入和配置决定，同一输入总是得到同一 slug。
+func (s SlugConfig) Apply(slug, lang string) string {
+	words := s.words(slug)
+	if len(words) == 0 {
+		return slug
+	}
+
+	stopwords := s.stopwords(lang)
+	acronyms := s.acronymSet()
+	var kept []string
+	for _, word := range words {
+		if stopwords[word] && !acronyms[word] {
+			continue
+		}
+		kept = append(kept, word)
+	}
+	if len(kept) == 0 {
+		kept = words
+	}
+
+	if s.MaxWords > 0 && len(kept) > s.MaxWords {
+		kept = kept[:s.MaxWords]
+	}
+	if s.MaxLength > 0 {
+		for len(kept) > 1 && len(strings.Join(kept, "-")) > s.MaxLength {
+			kept = kept[:len(kept)-1]
+		}
+		if len(kept[0]) > s.MaxLength {
+			kept[0] = kept[0][:s.MaxLength]
+		}
+	}
+	return strings.Join(kept, "-")
+}
+
+// Violations 返回 slug 不符合规范之处，符合时为空
+func (s SlugConfig) Violations(slug, lang string) []string {
+	var problems []string
+	words := strings.Split(slug, "-")
+	if s.MaxWords > 0 && len(words) > s.MaxWords {
+		problems = append(problems, fmt.Sprintf("%d 个单词，超过 %d", len(words), s.MaxWords))
+	}
+	if s.MaxLength > 0 && len(slug) > s.MaxLength {
+		problems = append(problems, fmt.Sprintf("%d 个字符，超过 %d", len(slug), s.MaxLength))
+	}
+	stopwords := s.stopwords(lang)
+	acronyms := s.acronymSet()
+	var found []string
+	for _, word := range words {
+		if stopwords[word] && !acronyms[word] {
+			found = append(found, word)
+		}
+	}
+	if len(found) > 0 && len(found) < len(words) {
+		problems = append(problems, "包含虚词 "+strings.Join(found, "、"))
+	}
+	if len(s.words(slug)) < len(words) {
+		problems = append(problems, "缩写被拆开")
+	}
+	if len(problems) == 0 && s.Apply(slug, lang) != slug {
+		problems = append(problems, "格式不规范")
+	}
+	return problems
+}
+
+// words 把 slug 拆成单词，并把被连字符拆开的缩写重新合并
+func (s SlugConfig) words(slug string) []string {
+	var parts []string
+	for _, part := range strings.Split(slug, "-") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	acronyms := s.acronymSet()
+	var words []string
+	for i := 0; i < len(parts); i++ {
+		merged := false
+		for j := len(parts); j > i+1; j-- {
+			if acronyms[strings.Join(parts[i:j], "")] {
+				words = append(words, strings.Join(parts[i:j], ""))
+				i = j - 1
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			words = append(words, parts[i])
+		}
+	}
+	return words
+}
+
+func (s SlugConfig) acronymSet() map[string]bool {
+	set := make(map[string]bool, len(s.Acronyms))
+	for _, acronym := range s.Acronyms {
+		set[acronym] = true
+	}
+	return set
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TaxonomySettings 覆盖单个分类法的默认处理方式，taxonomy_settings 中以分类法的单数名为键。
+type TaxonomySettings struct {
+	Dir            string `json:"dir"`             // 词条页面目录；留空时 tag、category 使用 paths 中的目录，其余为 tags_dir 的同级目录
+	Cache          string `json:"cache"`           // 词条译名的缓存命名空间，留空时为单数名
+	Policy         string `json:"policy"`          // translate（默认）翻译词条；copy 各语言保持原文，如作者名
+	Template       string `json:"template"`        // 新建词条页面的模板文件，留空使用内置模板
+	PromptTemplate string `json:"prompt_template"` // 翻译词条的用户消息模板，留空使用语言的模板
+}
+
+// Taxonomy 是合并 taxonomies 与 taxonomy_settings 后的分类法，复数名同时是文章前置数据中的键。
+type Taxonomy struct {
+	Singular string
+	Plural   string
+	TaxonomySettings
+}
+
+// slugCacheNamespace 是文章 slug 缓存使用的命名空间，分类法不能与之共用。
+const slugCacheNamespace = "article"
+
+// Label 返回分类法在提示信息中的名称。
+func (t Taxonomy) Label() string {
+	switch t.Singular {
+	case "tag":
+		return "标签"
+	case "category":
+		return "分类"
+	}
+	return t.Plural
+}
+
+// Translated 报告词条是否需要翻译为目标语言。
+func (t Taxonomy) Translated() bool {
+	return t.Policy != FieldCopy
+}
+
+// TaxonomyList 返回所有分类法，tag、category 在前，其余按单数名排序。
+// 未配置 taxonomies 时与 Hugo 的默认值一致。
+func (c *Config) TaxonomyList() []Taxonomy {
+	names := c.Taxonomies
+	if len(names) == 0 {
+		names = map[string]string{"tag": "tags", "category": "categories"}
+	}
+	singulars := make([]string, 0, len(names))
+	for singular := range names {
+		singulars = append(singulars, singular)
+	}
+	rank := func(singular string) int {
+		switch singular {
+		case "tag":
+			return 0
+		case "category":
+			return 1
+		}
+		return 2
+	}
+	sort.Slice(singulars, func(i, j int) bool {
+		if rank(singulars[i]) != rank(singulars[j]) {
+			return rank(singulars[i]) < rank(singulars[j])
+		}
+		return singulars[i] < singulars[j]
+	})
+
+	list := make([]Taxonomy, 0, len(singulars))
+	for _, singular := range singulars {
+		list = append(list, c.taxonomy(singular, names[singular]))
+	}
+	return list
+}
+
+// Taxonomy 按单数名或复数名查找分类法，不区分大小写。
+func (c *Config) Taxonomy(name string) (Taxonomy, bool) {
+	for _, taxonomy := range c.TaxonomyList() {
+		if strings.EqualFold(taxonomy.Singular, name) || strings.EqualFold(taxonomy.Plural, name) {
+			return taxonomy, true
+		}
+	}
+	return Taxonomy{}, false
+}
+
+func (c *Config) taxonomy(singular, plural string) Taxonomy {
+	taxonomy := Taxonomy{Singular: singular, Plural: plural, TaxonomySettings: c.TaxonomySettings[singular]}
+	if taxonomy.Dir == "" {
+		switch {
+		case singular == "tag":
+			taxonomy.Dir = c.Paths.TagsDir
+		case singular == "category":
+			taxonomy.Dir = c.Paths.CategoriesDir
+		case c.Paths.TagsDir != "":
+			taxonomy.Dir = filepath.Join(filepath.Dir(c.Paths.TagsDir), plural)
+		}
+	}
+	if taxonomy.Cache == "" {
+		taxonomy.Cache = singular
+	}
+	if taxonomy.Policy == "" {
+		taxonomy.Policy = FieldTranslate
+	}
+	return taxonomy
+}
+
+// TermCacheFile 返回词条缓存命名空间对应的缓存文件。tag、category 沿用 cache 中配置的文件名。
+func (c *Config) TermCacheFile(namespace string) string {
+	switch namespace {
+	case FieldCacheTag:
+		return c.Cache.TagFileName
+	case FieldCacheCategory:
+		return c.Cache.CategoryFileName
+	}
+	return filepath.Join(c.Paths.RuntimeDir, namespace+"_translations_cache.json")
+}
+
+// validateTaxonomies 检查 taxonomy_settings 的分类法名、策略与缓存命名空间。
+func (c *Config) validateTaxonomies() error {
+	for singular, settings := range c.TaxonomySettings {
+		if _, ok := c.Taxonomy(singular); !ok {
+			return fmt.Errorf("taxonomy_settings.%s 不是已配置的分类法", singular)
+		}
+		if settings.Policy != "" && settings.Policy != FieldTranslate && settings.Policy != FieldCopy {
+			return fmt.Errorf("taxonomy_settings.%s.policy %q 无效，应为 translate 或 copy", singular, settings.Policy)
+		}
+	}
+	for _, taxonomy := range c.TaxonomyList() {
+		if taxonomy.Cache == slugCacheNamespace || strings.ContainsAny(taxonomy.Cache, `/\`) {
+			return fmt.Errorf("分类法 %s 的缓存命名空间 %q 无效，请在 taxonomy_settings 中另行指定", taxonomy.Singular, taxonomy.Cache)
+		}
+	}
+	return nil
+}
+
+// applyTaxonomies 把分类法转为前置数据字段：需要翻译的分类法成为可翻译字段，文章中的词条与
+// 词条页面因此共用缓存和提示词；copy 策略的分类法同步时直接复制。
+func (c *Config) applyTaxonomies() {
+	var fields []TranslatableField
+	policies := make(map[string]string)
+	for _, taxonomy := range c.TaxonomyList() {
+		if !taxonomy.Translated() {
+			policies[strings.ToLower(taxonomy.Plural)] = FieldCopy
+			continue
+		}
+		fields = append(fields, TranslatableField{Path: taxonomy.Plural, Cache: taxonomy.Cache, PromptTemplate: taxonomy.PromptTemplate})
+	}
+	c.FrontMatter.taxonomyFields = fields
+	c.FrontMatter.taxonomyPolicies = policies
+}
@@ -0,0 +1,278 @@
+package frontmatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StringEditor 接收字段的点分路径（序列元素沿用所属字段的路径）与当前字符串值，
+// 返回新值及是否需要替换。
+type StringEditor func(path, value string) (string, bool)
+
+// EditStrings 对 front matter 中的字符串值逐个调用 editor。YAML 按 yaml.Node 的位置
+// 只替换被修改的标量文本，键顺序、注释、引号风格、流式列表与日期写法都保持原样；
+// TOML 与 JSON 回退为解码后按原格式重新编码。
+func (d *Document) EditStrings(editor StringEditor) error {
+	switch d.Format {
+	case FormatYAML:
+		return d.editYAMLStrings(editor)
+	case FormatTOML, FormatJSON:
+		data, err := d.DecodeMap()
+		if err != nil {
+			return err
+		}
+		editMapStrings(data, "", editor)
+		return d.Encode(data)
+	default:
+		return nil
+	}
+}
+
+func editMapStrings(data map[string]interface{}, prefix string, editor StringEditor) {
+	for key, value := range data {
+		data[key] = editValueStrings(value, joinPath(prefix, key), editor)
+	}
+}
+
+func editValueStrings(value interface{}, path string, editor StringEditor) interface{} {
+	switch v := value.(type) {
+	case string:
+		if replaced, ok := editor(path, v); ok {
+			return replaced
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = editValueStrings(item, path, editor)
+		}
+	case map[string]interface{}:
+		editMapStrings(v, path, editor)
+	}
+	return value
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// scalarEdit 描述一次文本替换，行列均从 0 开始，列按 rune 计数。
+type scalarEdit struct {
+	startLine, startCol int
+	endLine, endCol     int
+	text                string
+}
+
+func (d *Document) editYAMLStrings(editor StringEditor) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(d.FrontMatter), &root); err != nil {
+		return fmt.Errorf("yaml front matter 无效: %w", err)
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+
+	lines := strings.Split(d.FrontMatter, "\n")
+	var edits []scalarEdit
+	var walk func(node *yaml.Node, path string, flow bool)
+	walk = func(node *yaml.Node, path string, flow bool) {
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				walk(node.Content[i+1], joinPath(path, node.Content[i].Value), flow || node.Style&yaml.FlowStyle != 0)
+			}
+		case yaml.SequenceNode:
+			for _, item := range node.Content {
+				walk(item, path, flow || node.Style&yaml.FlowStyle != 0)
+			}
+		case yaml.ScalarNode:
+			if node.Tag != "!!str" {
+				return
+			}
+			replaced, ok := editor(path, node.Value)
+			if !ok || replaced == node.Value {
+				return
+			}
+			if edit, ok := scalarEditFor(lines, node, replaced, flow); ok {
+				edits = append(edits, edit)
+			}
+		}
+	}
+	walk(root.Content[0], "", false)
+
+	// 倒序应用，前面的位置不受后面替换长度变化的影响。
+	sort.Slice(edits, func(i, j int) bool {
+		if edits[i].startLine != edits[j].startLine {
+			return edits[i].startLine > edits[j].startLine
+		}
+		return edits[i].startCol > edits[j].startCol
+	})
+	for _, edit := range edits {
+		prefix := string([]rune(lines[edit.startLine])[:edit.startCol])
+		suffix := string([]rune(lines[edit.endLine])[edit.endCol:])
+		replacement := strings.Split(prefix+edit.text+suffix, "\n")
+		lines = append(lines[:edit.startLine], append(replacement, lines[edit.endLine+1:]...)...)
+	}
+	d.FrontMatter = strings.Join(lines, "\n")
+	return nil
+}
+
+// scalarEditFor 定位标量在源文本中的范围，并按原风格渲染新值。
+func scalarEditFor(lines []string, node *yaml.Node, value string, flow bool) (scalarEdit, bool) {
+	line, col := node.Line-1, node.Column-1
+	if line < 0 || line >= len(lines) {
+		return scalarEdit{}, false
+	}
+	runes := []rune(lines[line])
+	if col < 0 || col > len(runes) {
+		return scalarEdit{}, false
+	}
+
+	switch node.Style {
+	case yaml.DoubleQuotedStyle, yaml.SingleQuotedStyle:
+		if end, ok := quotedEnd(runes, col, node.Style); ok {
+			text := quoteDouble(value)
+			if node.Style == yaml.SingleQuotedStyle && !strings.Contains(value, "\n") {
+				text = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+			}
+			return scalarEdit{line, col, line, end, text}, true
+		}
+		return multilineEdit(lines, line, col, value, flow)
+	case yaml.LiteralStyle, yaml.FoldedStyle:
+		if flow {
+			return scalarEdit{}, false
+		}
+		endLine := blockEnd(lines, line)
+		indicator := strings.TrimSpace(string(runes[col:]))
+		indent := strings.Repeat(" ", leadingSpaces(lines[line])+2)
+		separator := "\n"
+		if node.Style == yaml.FoldedStyle {
+			separator = "\n\n"
+		}
+		var body []string
+		for _, part := range strings.Split(strings.TrimRight(value, "\n"), "\n") {
+			body = append(body, indent+part)
+		}
+		text := indicator + "\n" + strings.Join(body, separator)
+		return scalarEdit{line, col, endLine, len([]rune(lines[endLine])), text}, true
+	default:
+		end := plainEnd(runes, col, flow)
+		if strings.TrimSpace(string(runes[col:end])) != node.Value {
+			return multilineEdit(lines, line, col, value, flow)
+		}
+		text := value
+		if !plainSafe(value, flow) {
+			text = quoteDouble(value)
+		}
+		return scalarEdit{line, col, line, end, text}, true
+	}
+}
+
+// multilineEdit 处理跨行的普通或引号标量：在块上下文中整体替换为双引号单行值，
+// 流式上下文无法可靠定位结尾，保持原文。
+func multilineEdit(lines []string, line, col int, value string, flow bool) (scalarEdit, bool) {
+	if flow {
+		return scalarEdit{}, false
+	}
+	endLine := blockEnd(lines, line)
+	return scalarEdit{line, col, endLine, len([]rune(lines[endLine])), quoteDouble(value)}, true
+}
+
+func quotedEnd(runes []rune, start int, style yaml.Style) (int, bool) {
+	if start >= len(runes) {
+		return 0, false
+	}
+	quote := runes[start]
+	for i := start + 1; i < len(runes); i++ {
+		if style == yaml.DoubleQuotedStyle && runes[i] == '\\' {
+			i++
+			continue
+		}
+		if runes[i] != quote {
+			continue
+		}
+		if style == yaml.SingleQuotedStyle && i+1 < len(runes) && runes[i+1] == '\'' {
+			i++
+			continue
+		}
+		return i + 1, true
+	}
+	return 0, false
+}
+
+func plainEnd(runes []rune, start int, flow bool) int {
+	end := len(runes)
+	for i := start; i < len(runes); i++ {
+		if runes[i] == '#' && i > start && (runes[i-1] == ' ' || runes[i-1] == '\t') {
+			end = i
+			break
+		}
+		if flow && (runes[i] == ',' || runes[i] == ']' || runes[i] == '}') {
+			end = i
+			break
+		}
+	}
+	for end > start && (runes[end-1] == ' ' || runes[end-1] == '\t') {
+		end--
+	}
+	return end
+}
+
+// blockEnd 返回从 line 开始的块值最后一行：其后缩进更深的行与空行都属于该值。
+func blockEnd(lines []string, line int) int {
+	indent := leadingSpaces(lines[line])
+	end := line
+	for i := line + 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		if leadingSpaces(lines[i]) <= indent {
+			break
+		}
+		end = i
+	}
+	return end
+}
+
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func plainSafe(value string, flow bool) bool {
+	if value == "" || (flow && strings.ContainsAny(value, ",[]{}")) {
+		return false
+	}
+	out, err := yaml.Marshal(value)
+	return err == nil && string(out) == value+"\n"
+}
+
+func quoteDouble(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\x%02x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
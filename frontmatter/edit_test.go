@@ -0,0 +1,36 @@
+package frontmatter
+
+import (
+	"strings"
+	"testing"
+)
+
+func translateFixture(path, value string) (string, bool) {
+	translations := map[string]string{"自动集成": "Auto Integration", "博客": "Blog, Notes", "计算机": "Computer", "多行\n说明": "multi\nline"}
+	if translated, ok := translations[strings.TrimRight(value, "\n")]; ok && path != "slug" {
+		return translated, true
+	}
+	return value, false
+}
+
+func TestEditStringsPreservesYAMLLayout(t *testing.T) {
+	source := "# Documentation: https://example.com/\n\ntitle: \"自动集成\" # 标题\ntags: [travis, 博客]\ncategories:\n  - '计算机'\ndate: 2020-03-29T02:11:33+08:00\nslug: \"博客\"\ndescription: |\n  多行\n  说明\nfeatured: false"
+	doc := &Document{Format: FormatYAML, FrontMatter: source}
+	if err := doc.EditStrings(translateFixture); err != nil {
+		t.Fatal(err)
+	}
+	want := "# Documentation: https://example.com/\n\ntitle: \"Auto Integration\" # 标题\ntags: [travis, \"Blog, Notes\"]\ncategories:\n  - 'Computer'\ndate: 2020-03-29T02:11:33+08:00\nslug: \"博客\"\ndescription: |\n  multi\n  line\nfeatured: false"
+	if doc.FrontMatter != want {
+		t.Fatalf("YAML 布局被改写:\n%s", doc.FrontMatter)
+	}
+}
+
+func TestEditStringsReencodesTOML(t *testing.T) {
+	doc := &Document{Format: FormatTOML, FrontMatter: "title = \"自动集成\"\ntags = [\"博客\"]"}
+	if err := doc.EditStrings(translateFixture); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(doc.FrontMatter, `title = "Auto Integration"`) || !strings.Contains(doc.FrontMatter, `"Blog, Notes"`) {
+		t.Fatalf("TOML 字段未替换: %s", doc.FrontMatter)
+	}
+}
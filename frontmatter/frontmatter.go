@@ -0,0 +1,563 @@
+package frontmatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format 标识 Hugo 支持的 front matter 格式。
+type Format string
+
+const (
+	FormatNone Format = ""
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+	FormatJSON Format = "json"
+)
+
+const utf8BOM = "\ufeff"
+
+// Document 是拆分后的 Markdown 文件。FrontMatter 与 Body 内部统一使用 \n 换行，
+// 写回时再恢复源文件的换行风格与 BOM，避免编辑一个字段就改写整份文件。
+type Document struct {
+	Format      Format
+	FrontMatter string // 分隔符之间的原始文本；JSON 格式包含外层花括号
+	Body        string
+	LineEnding  string // "\n" 或 "\r\n"
+	BOM         bool
+}
+
+// ReadFile 读取并拆分 Markdown 文件。
+func ReadFile(path string) (*Document, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(content))
+}
+
+// Parse 按 Hugo 规则识别文件开头的 --- YAML、+++ TOML 或 { } JSON front matter。
+// 没有 front matter 的文件整体作为正文返回，不视为错误。
+func Parse(content string) (*Document, error) {
+	doc := &Document{LineEnding: "\n"}
+	if strings.HasPrefix(content, utf8BOM) {
+		doc.BOM = true
+		content = strings.TrimPrefix(content, utf8BOM)
+	}
+	if strings.Contains(content, "\r\n") {
+		doc.LineEnding = "\r\n"
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+	}
+
+	firstLine := content
+	if i := strings.IndexByte(content, '\n'); i >= 0 {
+		firstLine = content[:i]
+	}
+	switch strings.TrimSpace(firstLine) {
+	case "---":
+		return doc, doc.splitDelimited(content, "---", FormatYAML)
+	case "+++":
+		return doc, doc.splitDelimited(content, "+++", FormatTOML)
+	}
+	if strings.HasPrefix(content, "{") {
+		return doc, doc.splitJSON(content)
+	}
+
+	doc.Body = content
+	return doc, nil
+}
+
+func (d *Document) splitDelimited(content, delimiter string, format Format) error {
+	lines := strings.Split(content, "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == delimiter {
+			d.Format = format
+			d.FrontMatter = strings.Join(lines[1:i], "\n")
+			d.Body = strings.Join(lines[i+1:], "\n")
+			return nil
+		}
+	}
+	return fmt.Errorf("找不到 front matter 结束标记 %s", delimiter)
+}
+
+func (d *Document) splitJSON(content string) error {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return fmt.Errorf("JSON front matter 无效: %w", err)
+	}
+	end := int(decoder.InputOffset())
+	d.Format = FormatJSON
+	d.FrontMatter = content[:end]
+	d.Body = strings.TrimPrefix(content[end:], "\n")
+	return nil
+}
+
+// Header 返回带分隔符的 front matter，不含结尾换行。
+func (d *Document) Header() string {
+	switch d.Format {
+	case FormatYAML, FormatTOML:
+		delimiter := "---"
+		if d.Format == FormatTOML {
+			delimiter = "+++"
+		}
+		if d.FrontMatter == "" {
+			return delimiter + "\n" + delimiter
+		}
+		return delimiter + "\n" + d.FrontMatter + "\n" + delimiter
+	case FormatJSON:
+		return d.FrontMatter
+	default:
+		return ""
+	}
+}
+
+// String 按源文件的换行风格与 BOM 重新组装整份文件。
+func (d *Document) String() string {
+	content := d.Body
+	if d.Format != FormatNone {
+		content = d.Header() + "\n" + d.Body
+	}
+	return d.Restore(content)
+}
+
+// Restore 把内部统一的 \n 文本还原为源文件的换行风格，并按需补回 BOM。
+func (d *Document) Restore(content string) string {
+	if d.LineEnding == "\r\n" {
+		content = strings.ReplaceAll(content, "\n", "\r\n")
+	}
+	if d.BOM {
+		content = utf8BOM + content
+	}
+	return content
+}
+
+// Decode 以 YAML 语义解码 front matter。TOML 与 JSON 先转换为通用结构再交给 YAML，
+// 调用方的结构体只需声明 yaml 标签，日期等类型在三种格式间行为一致。
+func (d *Document) Decode(v interface{}) error {
+	switch d.Format {
+	case FormatNone:
+		return nil
+	case FormatYAML:
+		return yaml.Unmarshal([]byte(d.FrontMatter), v)
+	}
+	data, err := d.DecodeMap()
+	if err != nil {
+		return err
+	}
+	normalized, err := yaml.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(normalized, v)
+}
+
+// DecodeMap 将 front matter 解码为通用映射。
+func (d *Document) DecodeMap() (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	var err error
+	switch d.Format {
+	case FormatYAML:
+		err = yaml.Unmarshal([]byte(d.FrontMatter), &data)
+	case FormatTOML:
+		_, err = toml.Decode(d.FrontMatter, &data)
+	case FormatJSON:
+		err = json.Unmarshal([]byte(d.FrontMatter), &data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s front matter 无效: %w", d.Format, err)
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	return data, nil
+}
+
+// Encode 以文档原有格式重新生成 front matter。JSON 会保留原有顶层键顺序。
+func (d *Document) Encode(data map[string]interface{}) error {
+	switch d.Format {
+	case FormatYAML:
+		out, err := yaml.Marshal(data)
+		if err != nil {
+			return err
+		}
+		d.FrontMatter = strings.TrimRight(string(out), "\n")
+	case FormatTOML:
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(data); err != nil {
+			return err
+		}
+		d.FrontMatter = strings.TrimRight(buf.String(), "\n")
+	case FormatJSON:
+		out, err := encodeOrderedJSON(jsonKeyOrder(d.FrontMatter), data)
+		if err != nil {
+			return err
+		}
+		d.FrontMatter = out
+	default:
+		return fmt.Errorf("文件没有 front matter")
+	}
+	return nil
+}
+
+// GetString 读取顶层字符串字段；非字符串标量按其文本形式返回。
+func (d *Document) GetString(key string) (string, bool, error) {
+	data, err := d.DecodeMap()
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := data[key]
+	if !ok || value == nil {
+		return "", false, nil
+	}
+	if s, ok := value.(string); ok {
+		return s, true, nil
+	}
+	return fmt.Sprint(value), true, nil
+}
+
+// SetString 设置顶层字符串字段。YAML 与 TOML 只改写该字段所在行，其余内容逐字保留；
+// 字段缺失时追加在顶层键末尾。
+func (d *Document) SetString(key, value string) error {
+	return d.SetValue(key, value)
+}
+
+// SetValue 与 SetString 相同，但按值的类型写出字面量：布尔值不加引号，
+// 时间按 RFC 3339 写成 YAML/TOML 的日期时间，JSON 中为字符串。
+func (d *Document) SetValue(key string, value interface{}) error {
+	var literal string
+	switch v := value.(type) {
+	case string:
+		literal = fmt.Sprintf("%q", v)
+	case bool:
+		literal = strconv.FormatBool(v)
+	case time.Time:
+		literal = v.Format(time.RFC3339)
+		value = literal
+	default:
+		return fmt.Errorf("不支持的字段类型 %T", value)
+	}
+
+	switch d.Format {
+	case FormatYAML:
+		d.FrontMatter = setLine(d.FrontMatter, fmt.Sprintf("%s: %s", key, literal), func(line string) bool {
+			return strings.HasPrefix(line, key+":")
+		}, func(line string) bool {
+			return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+		}, false)
+		return nil
+	case FormatTOML:
+		d.FrontMatter = setLine(d.FrontMatter, fmt.Sprintf("%s = %s", key, literal), func(line string) bool {
+			name, _, found := strings.Cut(line, "=")
+			return found && strings.Trim(strings.TrimSpace(name), `"'`) == key
+		}, nil, true)
+		return nil
+	case FormatJSON:
+		data, err := d.DecodeMap()
+		if err != nil {
+			return err
+		}
+		data[key] = value
+		return d.Encode(data)
+	default:
+		return fmt.Errorf("找不到 front matter 起始标记")
+	}
+}
+
+// CopyField 把 src 的顶层字段 key 复制到 d，src 中没有该字段时从 d 删除。d 为 YAML 时
+// 只改写该字段的文本：src 也是 YAML 则逐字复制字段原文，日期写法、列表样式和注释随之保留；
+// TOML 与 JSON 解码后按原格式重新编码。
+func (d *Document) CopyField(src *Document, key string) error {
+	srcData, err := src.DecodeMap()
+	if err != nil {
+		return err
+	}
+	value, present := srcData[key]
+
+	switch d.Format {
+	case FormatYAML:
+		var text string
+		if present {
+			if src.Format == FormatYAML {
+				lines := strings.Split(src.FrontMatter, "\n")
+				start, end, _ := yamlField(lines, key)
+				text = strings.Join(lines[start:end], "\n")
+			} else {
+				out, err := yaml.Marshal(map[string]interface{}{key: value})
+				if err != nil {
+					return err
+				}
+				text = strings.TrimRight(string(out), "\n")
+			}
+		}
+		var lines []string
+		if d.FrontMatter != "" {
+			lines = strings.Split(d.FrontMatter, "\n")
+		}
+		start, end, found := yamlField(lines, key)
+		var replacement []string
+		if present {
+			replacement = []string{text}
+		}
+		if !found {
+			if !present {
+				return nil
+			}
+			start, end = len(lines), len(lines)
+		}
+		lines = append(lines[:start], append(replacement, lines[end:]...)...)
+		d.FrontMatter = strings.Join(lines, "\n")
+		return nil
+	case FormatTOML, FormatJSON:
+		data, err := d.DecodeMap()
+		if err != nil {
+			return err
+		}
+		if present {
+			data[key] = value
+		} else {
+			delete(data, key)
+		}
+		return d.Encode(data)
+	default:
+		return fmt.Errorf("找不到 front matter 起始标记")
+	}
+}
+
+// yamlField 返回 YAML 顶层字段 key 占据的行范围 [start, end)，包括缩进的续行、
+// 不缩进的序列项以及块标量中间的空行。
+func yamlField(lines []string, key string) (int, int, bool) {
+	continuation := func(line string) bool {
+		return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "-")
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, key+":") {
+			continue
+		}
+		end := i + 1
+		for next := end; next < len(lines); next++ {
+			if strings.TrimSpace(lines[next]) == "" {
+				continue
+			}
+			if !continuation(lines[next]) {
+				break
+			}
+			end = next + 1
+		}
+		return i, end, true
+	}
+	return 0, 0, false
+}
+
+// setLine 替换匹配的顶层行及其续行；tomlTables 为真时只在首个表头之前查找和追加，
+// 因为 TOML 表头之后的键属于该表而非顶层。
+func setLine(frontMatter, replacement string, match, continuation func(string) bool, tomlTables bool) string {
+	var lines []string
+	if frontMatter != "" {
+		lines = strings.Split(frontMatter, "\n")
+	}
+	limit := len(lines)
+	if tomlTables {
+		for i, line := range lines {
+			if strings.HasPrefix(strings.TrimSpace(line), "[") {
+				limit = i
+				break
+			}
+		}
+	}
+	for i := 0; i < limit; i++ {
+		if !match(lines[i]) {
+			continue
+		}
+		end := i + 1
+		for continuation != nil && end < limit && continuation(lines[end]) {
+			end++
+		}
+		lines = append(lines[:i], append([]string{replacement}, lines[end:]...)...)
+		return strings.Join(lines, "\n")
+	}
+	insertAt := limit
+	for insertAt > 0 && strings.TrimSpace(lines[insertAt-1]) == "" && tomlTables {
+		insertAt--
+	}
+	lines = append(lines[:insertAt], append([]string{replacement}, lines[insertAt:]...)...)
+	return strings.Join(lines, "\n")
+}
+
+// jsonKeyOrder 读取 JSON 对象的顶层键顺序；解析失败时返回空列表。
+func jsonKeyOrder(object string) []string {
+	decoder := json.NewDecoder(strings.NewReader(object))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return keys
+		}
+		key, _ := token.(string)
+		keys = append(keys, key)
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return keys
+		}
+	}
+	return keys
+}
+
+func encodeOrderedJSON(order []string, data map[string]interface{}) (string, error) {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, key := range order {
+		if _, ok := data[key]; ok && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+	var rest []string
+	for key := range data {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	keys = append(keys, rest...)
+
+	var buf strings.Builder
+	buf.WriteString("{\n")
+	for i, key := range keys {
+		value, err := marshalJSON(data[key])
+		if err != nil {
+			return "", err
+		}
+		name, _ := marshalJSON(key)
+		buf.WriteString("  " + name + ": " + strings.ReplaceAll(value, "\n", "\n  "))
+		if i < len(keys)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}")
+	return buf.String(), nil
+}
+
+func marshalJSON(value interface{}) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+// ScalarText 返回顶层标量字段在源文件中的字面文本（不区分键的大小写）。YAML 解码到
+// 通用映射时会把不带时区的日期当作 UTC，读取日期前需要原文再按站点时区解析；
+// 其他格式返回 false，由调用方使用解码后的值。
+func (d *Document) ScalarText(key string) (string, bool) {
+	if d.Format != FormatYAML {
+		return "", false
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(d.FrontMatter), &root); err != nil || len(root.Content) == 0 {
+		return "", false
+	}
+	mapping := root.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return "", false
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, key) && mapping.Content[i+1].Kind == yaml.ScalarNode {
+			return mapping.Content[i+1].Value, true
+		}
+	}
+	return "", false
+}
+
+// AppendString 把 value 追加到顶层字符串列表 key 的末尾，列表中已有该值时不做改动并返回 false。
+// YAML 块序列追加一行并沿用最后一项的缩进，其余写法（流式列表、TOML 数组）改写为单行列表；
+// 字段缺失时新建。
+func (d *Document) AppendString(key, value string) (bool, error) {
+	data, err := d.DecodeMap()
+	if err != nil {
+		return false, err
+	}
+	var list []string
+	switch existing := data[key].(type) {
+	case nil:
+	case string:
+		list = append(list, existing)
+	case []interface{}:
+		for _, item := range existing {
+			list = append(list, fmt.Sprint(item))
+		}
+	default:
+		return false, fmt.Errorf("字段 %s 不是列表", key)
+	}
+	for _, item := range list {
+		if item == value {
+			return false, nil
+		}
+	}
+	list = append(list, value)
+
+	quoted := make([]string, len(list))
+	for i, item := range list {
+		quoted[i] = fmt.Sprintf("%q", item)
+	}
+	literal := "[" + strings.Join(quoted, ", ") + "]"
+
+	switch d.Format {
+	case FormatYAML:
+		lines := strings.Split(d.FrontMatter, "\n")
+		if start, end, ok := yamlField(lines, key); ok && end > start+1 {
+			last := lines[end-1]
+			if item := strings.TrimLeft(last, " \t"); strings.HasPrefix(item, "- ") {
+				prefix := last[:len(last)-len(item)] + "- "
+				lines = append(lines[:end], append([]string{prefix + quoteDouble(value)}, lines[end:]...)...)
+				d.FrontMatter = strings.Join(lines, "\n")
+				return true, nil
+			}
+		}
+		d.FrontMatter = setLine(d.FrontMatter, fmt.Sprintf("%s: %s", key, literal), func(line string) bool {
+			return strings.HasPrefix(line, key+":")
+		}, func(line string) bool {
+			return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "-")
+		}, false)
+	case FormatTOML:
+		depth := 0
+		d.FrontMatter = setLine(d.FrontMatter, fmt.Sprintf("%s = %s", key, literal), func(line string) bool {
+			name, rest, found := strings.Cut(line, "=")
+			if !found || strings.Trim(strings.TrimSpace(name), `"'`) != key {
+				return false
+			}
+			depth = strings.Count(rest, "[") - strings.Count(rest, "]")
+			return true
+		}, func(line string) bool {
+			if depth <= 0 {
+				return false
+			}
+			depth += strings.Count(line, "[") - strings.Count(line, "]")
+			return true
+		}, true)
+	case FormatJSON:
+		data[key] = list
+		if err := d.Encode(data); err != nil {
+			return false, err
+		}
+	default:
+		return false, fmt.Errorf("找不到 front matter 起始标记")
+	}
+	return true, nil
+}
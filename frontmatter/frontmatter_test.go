@@ -0,0 +1,128 @@
+package frontmatter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDetectsAllHugoFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		format  Format
+	}{
+		{"yaml", "---\ntitle: 示例\n---\n正文\n", FormatYAML},
+		{"toml", "+++\ntitle = \"示例\"\n+++\n正文\n", FormatTOML},
+		{"json", "{\n  \"title\": \"示例\"\n}\n正文\n", FormatJSON},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := Parse(test.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fm struct {
+				Title string `yaml:"title"`
+			}
+			if err := doc.Decode(&fm); err != nil {
+				t.Fatal(err)
+			}
+			if doc.Format != test.format || fm.Title != "示例" || doc.Body != "正文\n" {
+				t.Fatalf("format=%q title=%q body=%q", doc.Format, fm.Title, doc.Body)
+			}
+			if doc.String() != test.content {
+				t.Fatalf("往返结果不一致: %q", doc.String())
+			}
+		})
+	}
+}
+
+func TestSetStringKeepsTOMLKeysAtTopLevel(t *testing.T) {
+	doc, err := Parse("+++\ntitle = \"示例\"\n\n[params]\nslug = \"nested\"\n+++\n正文")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetString("slug", "top"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(doc.FrontMatter, "title = \"示例\"\nslug = \"top\"\n\n[params]\nslug = \"nested\"") {
+		t.Fatalf("slug 未写入顶层: %q", doc.FrontMatter)
+	}
+}
+
+func TestSetStringPreservesLineEndingAndBOM(t *testing.T) {
+	doc, err := Parse("\ufeff---\r\ntitle: 示例\r\nslug: old\r\n---\r\n正文\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetString("slug", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.String(); got != "\ufeff---\r\ntitle: 示例\r\nslug: \"new\"\r\n---\r\n正文\r\n" {
+		t.Fatalf("换行或 BOM 未保留: %q", got)
+	}
+}
+
+func TestCopyFieldKeepsYAMLText(t *testing.T) {
+	src := &Document{Format: FormatYAML, FrontMatter: "title: 示例\ntags:\n- a\n- b\ndate: 2024-01-02"}
+	doc := &Document{Format: FormatYAML, FrontMatter: "title: Example\ntags: [x]\nlegacy: true"}
+	for _, key := range []string{"tags", "date", "legacy"} {
+		if err := doc.CopyField(src, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := "title: Example\ntags:\n- a\n- b\ndate: 2024-01-02"; doc.FrontMatter != want {
+		t.Fatalf("got %q", doc.FrontMatter)
+	}
+}
+
+func TestEncodeJSONKeepsKeyOrder(t *testing.T) {
+	doc, err := Parse("{\"title\": \"示例\", \"date\": \"2024-01-01\"}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetString("slug", "example"); err != nil {
+		t.Fatal(err)
+	}
+	title, date, slug := strings.Index(doc.FrontMatter, "title"), strings.Index(doc.FrontMatter, "date"), strings.Index(doc.FrontMatter, "slug")
+	if title > date || date > slug {
+		t.Fatalf("JSON 键顺序被打乱: %s", doc.FrontMatter)
+	}
+}
+
+func TestParseRejectsUnclosedFrontMatter(t *testing.T) {
+	if _, err := Parse("---\ntitle: 示例\n正文"); err == nil {
+		t.Fatal("缺少结束标记应返回错误")
+	}
+}
+
+func TestAppendStringKeepsListStyle(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"yaml block", "---\ntitle: 示例\naliases:\n  - old\ndraft: false\n---\n", "---\ntitle: 示例\naliases:\n  - old\n  - \"older\"\ndraft: false\n---\n"},
+		{"yaml flow", "---\naliases: [old]\n---\n", "---\naliases: [\"old\", \"older\"]\n---\n"},
+		{"yaml missing", "---\ntitle: 示例\n---\n", "---\ntitle: 示例\naliases: [\"older\"]\n---\n"},
+		{"toml multiline", "+++\naliases = [\n  \"old\",\n]\ntitle = \"示例\"\n[params]\nx = 1\n+++\n", "+++\naliases = [\"old\", \"older\"]\ntitle = \"示例\"\n[params]\nx = 1\n+++\n"},
+		{"json", "{\n  \"title\": \"示例\",\n  \"aliases\": [\"old\"]\n}\n", "{\n  \"title\": \"示例\",\n  \"aliases\": [\n    \"old\",\n    \"older\"\n  ]\n}\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := Parse(test.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed, err := doc.AppendString("aliases", "older"); err != nil || !changed {
+				t.Fatalf("changed=%v err=%v", changed, err)
+			}
+			if doc.String() != test.want {
+				t.Fatalf("got %q", doc.String())
+			}
+			if changed, _ := doc.AppendString("aliases", "older"); changed {
+				t.Fatal("已有的值不应重复追加")
+			}
+		})
+	}
+}
@@ -1,19 +1,34 @@
 package generator
 
 import (
+	"errors"
 	"fmt"
+	"hugo-content-suite/config"
+	"hugo-content-suite/frontmatter"
+	"hugo-content-suite/layout"
+	"hugo-content-suite/manifest"
 	"hugo-content-suite/models"
 	"hugo-content-suite/scanner"
 	"hugo-content-suite/translator"
 	"hugo-content-suite/utils"
 	"os"
-	"strings"
+	"time"
 )
 
 // ArticleSlugGenerator 文章slug生成器
 type ArticleSlugGenerator struct {
 	contentDir       string
 	translationUtils *translator.TranslationUtils
+	filter           models.ArticleFilter
+	conflictPolicy   manifest.Policy
+	layout           layout.Layout
+	forceLocked      bool
+	slugEngine       string
+	owners           map[string]map[string]string // 栏目 → slug → 使用该 slug 的文章，挑选 slug 时用于检查重复
+	modelChecked     bool
+	modelReady       bool
+	skipped          int
+	contentRoot      string // 源语言内容根目录，文章相对它的第一层目录为栏目，slug 在栏目内去重
 }
 
 // ArticleSlugPreview 文章slug预览信息
@@ -23,6 +38,9 @@ type ArticleSlugPreview struct {
 	CurrentSlug string
 	NewSlug     string
 	Status      string // "missing", "update", "skip"
+	Collision   string // 翻译得到的 slug 与同一栏目中其他文章重复时的说明，NewSlug 为去重后的结果
+	Published   bool   // 文章已发布，修改 slug 时旧地址写入各语言页面的 aliases
+	Locked      bool   // 已发布文章的 slug 受 slug.lock 保护，只有强制修改时才会更新
 }
 
 // 实现 StatusLike 接口
@@ -41,27 +59,99 @@ func NewArticleSlugGenerator(contentDir string) *ArticleSlugGenerator {
 	return &ArticleSlugGenerator{
 		contentDir:       contentDir,
 		translationUtils: translator.NewTranslationUtils(),
+		layout:           layout.ForContentDir(contentDir),
+		slugEngine:       config.GetGlobalConfig().Slug.Engine,
+		contentRoot:      contentRoot(config.GetGlobalConfig(), contentDir),
 	}
 }
 
+// SetSlugEngine 设置本次使用的 slug 生成方式（llm 或 pinyin），默认取 slug.engine
+func (g *ArticleSlugGenerator) SetSlugEngine(engine string) {
+	g.slugEngine = engine
+}
+
+// SetFilter 设置筛选条件，之后的扫描只处理满足条件的文章
+func (g *ArticleSlugGenerator) SetFilter(filter models.ArticleFilter) {
+	g.filter = filter
+}
+
+// SkippedFiles 返回上次扫描中 front matter 无法解析而跳过的文件数
+func (g *ArticleSlugGenerator) SkippedFiles() int {
+	return g.skipped
+}
+
+// SetConflictPolicy 设置 slug 在上次生成后被手动修改时的处理方式，默认跳过
+func (g *ArticleSlugGenerator) SetConflictPolicy(policy manifest.Policy) {
+	g.conflictPolicy = policy
+}
+
+// SetForceLocked 设置是否强制修改受 slug.lock 保护的已发布文章的 slug，默认保持原 slug
+func (g *ArticleSlugGenerator) SetForceLocked(force bool) {
+	g.forceLocked = force
+}
+
+// LockedSlugs 返回预览中 slug 受保护、需要强制才能修改的文章
+func (g *ArticleSlugGenerator) LockedSlugs(previews []ArticleSlugPreview) []string {
+	var locked []string
+	for _, preview := range previews {
+		if preview.Status == "update" && preview.Locked {
+			locked = append(locked, preview.FilePath)
+		}
+	}
+	return locked
+}
+
+// ModifiedSlugs 返回预览中 slug 在上次生成后被手动修改过的文章，以及更新 slug 时会同步、
+// 但 slug 被手动设置过的译文
+func (g *ArticleSlugGenerator) ModifiedSlugs(previews []ArticleSlugPreview) []string {
+	var modified []string
+	for _, preview := range previews {
+		if preview.Status != "skip" && manifest.Modified(preview.FilePath, manifest.KindSlug, preview.CurrentSlug) {
+			modified = append(modified, preview.FilePath)
+		}
+		if preview.Status == "update" {
+			modified = append(modified, g.modifiedTranslationSlugs(preview)...)
+		}
+	}
+	return modified
+}
+
+// modifiedTranslationSlugs 返回 preview 的已有译文中 slug 与新 slug 不同且被手动设置过的译文
+func (g *ArticleSlugGenerator) modifiedTranslationSlugs(preview ArticleSlugPreview) []string {
+	var modified []string
+	for _, lang := range config.GetGlobalConfig().Language.TargetLanguages {
+		path := g.layout.TranslationPath(preview.FilePath, lang)
+		if path == "" || !utils.FileExists(path) {
+			continue
+		}
+		current := pageStringField(path, "slug")
+		if current != preview.NewSlug && translationSlugModified(path, current, preview.CurrentSlug) {
+			modified = append(modified, path)
+		}
+	}
+	return modified
+}
+
 // PrepareArticleSlugs 预处理文章slug生成
 func (g *ArticleSlugGenerator) PrepareArticleSlugs() ([]ArticleSlugPreview, int, int, error) {
 	var previews []ArticleSlugPreview
 
 	// 扫描文章 - 使用基础扫描函数，不需要内容详情
-	articles, err := scanner.ScanArticles(g.contentDir)
+	articles, issues, err := scanner.ScanArticlesWithIssues(g.contentDir, false)
 	if err != nil {
 		return nil, 0, 0, fmt.Errorf("扫描文章失败: %v", err)
 	}
-
-	// 测试LM Studio连接
-	fmt.Print("🔗 测试LM Studio连接... ")
-	if err := g.translationUtils.TestConnection(); err != nil {
-		fmt.Printf("❌ 失败 (%v)\n", err)
-		fmt.Println("⚠️ 无法连接AI翻译，终止操作")
-		return nil, 0, 0, fmt.Errorf("AI翻译连接失败: %v", err)
-	} else {
-		fmt.Println("✅ 成功")
+	g.skipped = scanner.Skipped(issues)
+	all := articles
+	articles = g.filter.Apply(articles, g.contentDir)
+
+	// 使用模型时测试LM Studio连接，连接失败时按配置改用拼音
+	engine := g.slugEngine
+	if engine != config.SlugEnginePinyin {
+		g.modelChecked, g.modelReady = true, connectModel(g.translationUtils)
+		if engine, err = slugEngineFor(engine, g.modelReady); err != nil {
+			return nil, 0, 0, err
+		}
 	}
 
 	// 收集需要处理的文章标题
@@ -80,27 +170,46 @@ func (g *ArticleSlugGenerator) PrepareArticleSlugs() ([]ArticleSlugPreview, int,
 
 	fmt.Printf("🌐 正在生成 %d 个文章的slug...\n", len(titleList))
 
-	// 使用AI批量翻译slug
-	slugMap, err := g.translationUtils.TranslateArticlesSlugs(titleList)
+	// 使用AI批量翻译或拼音生成slug，格式化后按 slug 规范去掉虚词、限制长度
+	slugMap, err := generateSlugs(titleList, engine, g.translationUtils.TranslateArticlesSlugs)
 	if err != nil {
 		fmt.Printf("⚠️ 批量翻译失败: %v\n", err)
 		return nil, 0, 0, fmt.Errorf("批量翻译失败: %v", err)
 	}
 
-	// 格式化所有slug
-	for title, slug := range slugMap {
-		slugMap[title] = utils.FormatSlugField(slug)
+	// 挑选时人工选定的 slug 按文章固定在缓存中，优先于本次按标题生成的结果
+	paths := make([]string, len(validArticles))
+	for i, article := range validArticles {
+		paths[i] = article.FilePath
+	}
+	pinned := g.translationUtils.PinnedArticleSlugs(paths)
+	proposed := make(map[string]string, len(validArticles))
+	for _, article := range validArticles {
+		proposed[article.FilePath] = slugMap[article.Title]
+		if slug, ok := pinned[article.FilePath]; ok {
+			proposed[article.FilePath] = slug
+		}
+	}
+	if len(pinned) > 0 {
+		fmt.Printf("📌 使用已固定的 slug: %d 个\n", len(pinned))
 	}
 
+	resolved := resolveArticleSlugs(g.contentRoot, all, validArticles, proposed, g.extractSlugFromFile)
+	g.owners = slugOwners(g.contentRoot, all, validArticles, resolved)
+
 	fmt.Printf("\n📊 正在分析文章slug状态...\n")
 	createCount := 0
 	updateCount := 0
+	collisionCount := 0
+	lockedCount := 0
+	lockPublished := config.GetGlobalConfig().Slug.LockPublished()
+	now := time.Now()
 
 	for i, article := range validArticles {
 		fmt.Printf("  [%d/%d] 检查: %s", i+1, len(validArticles), article.Title)
 
 		currentSlug := g.extractSlugFromFile(article.FilePath)
-		newSlug := slugMap[article.Title]
+		newSlug := resolved[i].Slug
 
 		var status string
 		if currentSlug == "" {
@@ -115,6 +224,16 @@ func (g *ArticleSlugGenerator) PrepareArticleSlugs() ([]ArticleSlugPreview, int,
 			status = "skip"
 			fmt.Printf(" ✅ 已是最新\n")
 		}
+		if resolved[i].Collision != "" {
+			collisionCount++
+			fmt.Printf("     ⚠️ slug %s，使用 %s\n", resolved[i].Collision, newSlug)
+		}
+		published := article.Published(now)
+		locked := status == "update" && published && lockPublished
+		if locked {
+			lockedCount++
+			fmt.Printf("     🔒 已发布，slug %s 受保护\n", currentSlug)
+		}
 
 		preview := ArticleSlugPreview{
 			FilePath:    article.FilePath,
@@ -122,6 +241,9 @@ func (g *ArticleSlugGenerator) PrepareArticleSlugs() ([]ArticleSlugPreview, int,
 			CurrentSlug: currentSlug,
 			NewSlug:     newSlug,
 			Status:      status,
+			Collision:   resolved[i].Collision,
+			Published:   published,
+			Locked:      locked,
 		}
 		previews = append(previews, preview)
 	}
@@ -129,11 +251,58 @@ func (g *ArticleSlugGenerator) PrepareArticleSlugs() ([]ArticleSlugPreview, int,
 	fmt.Printf("\n📈 统计结果:\n")
 	fmt.Printf("   ✨ 需要新建: %d 个\n", createCount)
 	fmt.Printf("   🔄 需要更新: %d 个\n", updateCount)
+	if collisionCount > 0 {
+		fmt.Printf("   ⚠️  slug 重复已加后缀: %d 个\n", collisionCount)
+	}
+	if lockedCount > 0 {
+		fmt.Printf("   🔒 已发布、需强制才会更新: %d 个\n", lockedCount)
+	}
 	fmt.Printf("   📦 总计: %d 个\n", len(previews))
 
 	return previews, createCount, updateCount, nil
 }
 
+// resolveArticleSlugs 在内容根目录 root 的每个栏目内为 articles 去重 slug，返回与 articles 一一对应的结果。
+// proposed 为文章文件 → 本次生成的 slug；all 中不参与本次处理的文章（被筛选排除或没有标题）的现有 slug 视为已占用
+func resolveArticleSlugs(root string, all, articles []models.Article, proposed map[string]string, currentSlug func(string) string) []slugResolution {
+	processing := make(map[string]bool, len(articles))
+	for _, article := range articles {
+		processing[article.FilePath] = true
+	}
+	taken := make(map[string]map[string]string)
+	for _, article := range all {
+		if processing[article.FilePath] || article.Slug == "" {
+			continue
+		}
+		section := articleSection(root, article.FilePath)
+		if taken[section] == nil {
+			taken[section] = make(map[string]string)
+		}
+		taken[section][article.Slug] = article.FilePath
+	}
+
+	claims := make(map[string][]slugClaim)
+	indexes := make(map[string][]int)
+	for i, article := range articles {
+		section := articleSection(root, article.FilePath)
+		claims[section] = append(claims[section], slugClaim{
+			Owner:    article.FilePath,
+			Proposed: proposed[article.FilePath],
+			Current:  currentSlug(article.FilePath),
+			Date:     article.Date,
+		})
+		indexes[section] = append(indexes[section], i)
+	}
+
+	results := make([]slugResolution, len(articles))
+	for section, sectionClaims := range claims {
+		for j, result := range resolveSlugs(sectionClaims, taken[section]) {
+			results[indexes[section][j]] = result
+		}
+	}
+	return results
+}
+
 // GenerateArticleSlugsWithMode 根据模式生成文章slug
 func (g *ArticleSlugGenerator) GenerateArticleSlugsWithMode(targetPreviews []ArticleSlugPreview, mode string) error {
 	fmt.Println("\n📝 文章Slug生成器 (模式选择)")
@@ -153,6 +322,7 @@ func (g *ArticleSlugGenerator) GenerateArticleSlugsWithMode(targetPreviews []Art
 func (g *ArticleSlugGenerator) processTargetPreviews(targetPreviews []ArticleSlugPreview) error {
 	createdCount := 0
 	updatedCount := 0
+	skippedCount := 0
 	errorCount := 0
 
 	fmt.Printf("\n📝 正在生成文章slug...\n")
@@ -161,7 +331,27 @@ func (g *ArticleSlugGenerator) processTargetPreviews(targetPreviews []ArticleSlu
 	for i, preview := range targetPreviews {
 		fmt.Printf("  [%d/%d] %s", i+1, len(targetPreviews), preview.Title)
 
+		if preview.Status == "update" && preview.Locked && !g.forceLocked {
+			fmt.Printf(" 🔒 已发布，保持 slug %s\n", preview.CurrentSlug)
+			skippedCount++
+			continue
+		}
+
 		var err error
+		if newFile, guardErr := g.guardSlug(preview); guardErr != nil || newFile != "" {
+			if errors.Is(guardErr, manifest.ErrModified) {
+				fmt.Printf(" ⏭️  slug 已手动修改，跳过\n")
+			} else if guardErr != nil {
+				fmt.Printf(" ❌ 失败\n")
+				fmt.Printf("     错误: %v\n", guardErr)
+				errorCount++
+				continue
+			} else {
+				fmt.Printf(" 📄 slug 已手动修改，另存为 %s\n", newFile)
+			}
+			skippedCount++
+			continue
+		}
 		if preview.Status == "missing" {
 			err = g.addSlugToFile(preview.FilePath, preview.NewSlug)
 			if err == nil {
@@ -175,6 +365,22 @@ func (g *ArticleSlugGenerator) processTargetPreviews(targetPreviews []ArticleSlu
 				fmt.Printf(" 🔄 更新\n")
 				fmt.Printf("     slug: %s -> %s\n", preview.CurrentSlug, preview.NewSlug)
 				updatedCount++
+				saved, syncErrs := g.syncSlugChange(preview.FilePath, preview.CurrentSlug, preview.NewSlug, preview.Published)
+				for _, newFile := range saved {
+					fmt.Printf("     📄 译文 slug 已手动修改，新结果另存为 %s\n", newFile)
+				}
+				for _, syncErr := range syncErrs {
+					if errors.Is(syncErr, manifest.ErrModified) {
+						fmt.Printf("     ⏭️  译文 slug 已手动修改，保持不变: %v\n", syncErr)
+					} else {
+						fmt.Printf("     ⚠️ 同步 slug 失败: %v\n", syncErr)
+					}
+				}
+			}
+		}
+		if err == nil {
+			if recordErr := manifest.Record(preview.FilePath, manifest.KindSlug, preview.NewSlug); recordErr != nil {
+				fmt.Printf("     ⚠️ 更新生成清单失败: %v\n", recordErr)
 			}
 		}
 
@@ -188,6 +394,9 @@ func (g *ArticleSlugGenerator) processTargetPreviews(targetPreviews []ArticleSlu
 	fmt.Printf("\n🎉 文章slug生成完成！\n")
 	fmt.Printf("   ✨ 新建: %d 个\n", createdCount)
 	fmt.Printf("   🔄 更新: %d 个\n", updatedCount)
+	if skippedCount > 0 {
+		fmt.Printf("   ⏭️  保留手动修改或受保护的 slug: %d 个\n", skippedCount)
+	}
 	if errorCount > 0 {
 		fmt.Printf("   ❌ 失败: %d 个\n", errorCount)
 	}
@@ -198,12 +407,12 @@ func (g *ArticleSlugGenerator) processTargetPreviews(targetPreviews []ArticleSlu
 
 // extractSlugFromFile 从文件中提取现有的slug
 func (g *ArticleSlugGenerator) extractSlugFromFile(filePath string) string {
-	content, err := os.ReadFile(filePath)
+	doc, err := frontmatter.ReadFile(filePath)
 	if err != nil {
 		return ""
 	}
-	_, value, found, err := slugLineRange(string(content))
-	if err != nil || !found {
+	value, _, err := doc.GetString("slug")
+	if err != nil {
 		return ""
 	}
 	return value
@@ -219,52 +428,136 @@ func (g *ArticleSlugGenerator) updateSlugInFile(filePath, oldSlug, newSlug strin
 	return writeSlug(filePath, newSlug)
 }
 
-// writeSlug 只在 YAML front matter 范围内更新 slug，避免正文示例中的同名字段被替换。
-func writeSlug(filePath, slug string) error {
-	content, err := os.ReadFile(filePath)
+// syncSlugChange 把源文件改为 newSlug 后，同样写入各语言已有译文的 slug。published 为真时
+// 把旧 slug 作为相对别名追加到源文件及 slug 实际改变的译文的 aliases 中：Hugo 把相对别名解析到
+// 页面所在的同一层目录，旧地址因此在每种语言下都重定向到该语言的新地址，与 permalinks 配置无关。
+// 译文的 slug 被手动设置过时按冲突策略处理，返回另存的 .new 文件；跳过的译文以 ErrModified 报告
+func (g *ArticleSlugGenerator) syncSlugChange(sourcePath, oldSlug, newSlug string, published bool) ([]string, []error) {
+	var saved []string
+	var errs []error
+	if published {
+		if err := appendAlias(sourcePath, oldSlug); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sourcePath, err))
+		}
+	}
+	for _, lang := range config.GetGlobalConfig().Language.TargetLanguages {
+		path := g.layout.TranslationPath(sourcePath, lang)
+		if path == "" || !utils.FileExists(path) {
+			continue
+		}
+		written, err := updateTranslationSlug(path, oldSlug, newSlug, published, g.conflictPolicy)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		} else if written != path {
+			saved = append(saved, written)
+		}
+	}
+	return saved, errs
+}
+
+// translationSlugModified 报告译文的 slug 是否被手动设置：与源文件原 slug 相同视为随源文件复制，
+// 否则按生成清单中该译文的 slug 记录判断，没有记录的非空 slug 视为手动设置
+func translationSlugModified(path, current, sourceSlug string) bool {
+	return current != sourceSlug && manifest.Modified(path, manifest.KindSlug, current)
+}
+
+// updateTranslationSlug 把译文的 slug 改为 slug，原有 slug 不同且 alias 为真时追加到 aliases，
+// 经由生成清单写回并记录新 slug，返回写入的路径；slug 已一致时不修改。
+// 译文的 slug 被手动设置过时按 policy 处理：覆盖、另存为 .new 文件，默认返回 ErrModified
+func updateTranslationSlug(path, sourceSlug, slug string, alias bool, policy manifest.Policy) (string, error) {
+	doc, err := frontmatter.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	current, _, _ := doc.GetString("slug")
+	if current == slug {
+		return path, nil
+	}
+	modified := translationSlugModified(path, current, sourceSlug)
+	if modified && policy != manifest.PolicyOverwrite && policy != manifest.PolicySaveNew {
+		return "", manifest.ErrModified
+	}
+	if err := doc.SetString("slug", slug); err != nil {
+		return "", err
+	}
+	if alias && current != "" {
+		if _, err := doc.AppendString("aliases", current); err != nil {
+			return "", err
+		}
+	}
+	if modified && policy == manifest.PolicySaveNew {
+		newFile := path + manifest.NewSuffix
+		return newFile, utils.WriteFileContent(newFile, doc.String())
+	}
+	if err := manifest.UpdateFile(path, manifest.KindTranslation, doc.String()); err != nil {
+		return "", err
+	}
+	if err := manifest.Record(path, manifest.KindSlug, slug); err != nil {
+		return "", fmt.Errorf("更新生成清单失败: %w", err)
+	}
+	return path, nil
+}
+
+// appendAlias 向源文件的 aliases 追加 alias，文件按原有权限写回
+func appendAlias(path, alias string) error {
+	doc, err := frontmatter.ReadFile(path)
 	if err != nil {
 		return err
 	}
-	lines := strings.Split(string(content), "\n")
-	end, _, found, err := slugLineRange(string(content))
+	changed, err := doc.AppendString("aliases", alias)
+	if err != nil || !changed {
+		return err
+	}
+	info, err := os.Stat(path)
 	if err != nil {
 		return err
 	}
-	if found {
-		for i := 1; i < end; i++ {
-			if strings.HasPrefix(strings.TrimSpace(lines[i]), "slug:") {
-				lines[i] = fmt.Sprintf("slug: %q", slug)
-				break
-			}
+	return os.WriteFile(path, []byte(doc.String()), info.Mode())
+}
+
+// guardSlug 检查 slug 是否在上次生成后被手动修改。未修改或策略为覆盖时返回空路径，
+// 由调用方正常写入；策略为另存时把新结果写到 .new 文件并返回其路径；默认返回 ErrModified。
+func (g *ArticleSlugGenerator) guardSlug(preview ArticleSlugPreview) (string, error) {
+	if !manifest.Modified(preview.FilePath, manifest.KindSlug, preview.CurrentSlug) {
+		return "", nil
+	}
+	switch g.conflictPolicy {
+	case manifest.PolicyOverwrite:
+		return "", nil
+	case manifest.PolicySaveNew:
+		content, err := renderSlug(preview.FilePath, preview.NewSlug)
+		if err != nil {
+			return "", err
 		}
-	} else {
-		lines = append(lines[:end], append([]string{fmt.Sprintf("slug: %q", slug)}, lines[end:]...)...)
+		newFile := preview.FilePath + manifest.NewSuffix
+		return newFile, utils.WriteFileContent(newFile, content)
+	default:
+		return "", manifest.ErrModified
+	}
+}
+
+// writeSlug 只在 front matter 范围内更新 slug，避免正文示例中的同名字段被替换；
+// 文件按原有格式、换行风格写回。
+func writeSlug(filePath, slug string) error {
+	content, err := renderSlug(filePath, slug)
+	if err != nil {
+		return err
 	}
 	info, err := os.Stat(filePath)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), info.Mode())
+	return os.WriteFile(filePath, []byte(content), info.Mode())
 }
 
-// slugLineRange 返回 front matter 结束行、slug 值及其存在状态。
-func slugLineRange(content string) (int, string, bool, error) {
-	lines := strings.Split(content, "\n")
-	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
-		return 0, "", false, fmt.Errorf("找不到 front matter 起始标记")
+// renderSlug 返回设置了新 slug 的完整文件内容。
+func renderSlug(filePath, slug string) (string, error) {
+	doc, err := frontmatter.ReadFile(filePath)
+	if err != nil {
+		return "", err
 	}
-	for i := 1; i < len(lines); i++ {
-		if strings.TrimSpace(lines[i]) == "---" {
-			return i, "", false, nil
-		}
-		if strings.HasPrefix(strings.TrimSpace(lines[i]), "slug:") {
-			value := strings.Trim(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), "slug:")), "\"'")
-			for j := i + 1; j < len(lines); j++ {
-				if strings.TrimSpace(lines[j]) == "---" {
-					return j, value, true, nil
-				}
-			}
-		}
+	if err := doc.SetString("slug", slug); err != nil {
+		return "", err
 	}
-	return 0, "", false, fmt.Errorf("找不到 front matter 结束标记")
+	return doc.String(), nil
 }
@@ -1,10 +1,14 @@
 package generator
 
 import (
+	"errors"
+	"hugo-content-suite/config"
+	"hugo-content-suite/manifest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestWriteSlugOnlyChangesFrontMatter(t *testing.T) {
@@ -38,3 +42,198 @@ func TestWriteSlugAddsMissingField(t *testing.T) {
 		t.Fatalf("未在 front matter 末尾新增 slug: %s", got)
 	}
 }
+
+func TestResolveSlugsDisambiguatesDeterministically(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	claims := []slugClaim{
+		{Owner: "b.md", Proposed: "go-intro", Date: day(2)},
+		{Owner: "a.md", Proposed: "go-intro", Date: day(1)},
+		{Owner: "c.md", Proposed: "go-intro", Current: "go-intro-3", Date: day(2)},
+		{Owner: "d.md", Proposed: "rust", Date: day(5)},
+		{Owner: "e.md", Proposed: "draft-notes"},
+	}
+	taken := map[string]string{"rust": "old.md", "rust-20240105": "older.md"}
+
+	var got []string
+	for _, result := range resolveSlugs(claims, taken) {
+		got = append(got, result.Slug)
+	}
+	want := []string{"go-intro-20240102", "go-intro", "go-intro-3", "rust-2", "draft-notes"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("slugs=%v want %v", got, want)
+	}
+
+	// 已去重的结果作为现有 slug 再次处理时保持不变
+	for i := range claims {
+		claims[i].Current = got[i]
+	}
+	for i, result := range resolveSlugs(claims, taken) {
+		if result.Slug != got[i] {
+			t.Fatalf("第二次处理 %s 得到 %s，应为 %s", claims[i].Owner, result.Slug, got[i])
+		}
+	}
+	if results := resolveSlugs(claims, taken); results[1].Collision != "" || results[0].Collision == "" {
+		t.Fatalf("最早的文章保留原 slug，其余标记重复: %+v", results)
+	}
+}
+
+func TestLockedSlugsNeedForceAndKeepOldURLAsAlias(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "post.md")
+	translation := filepath.Join(dir, "post.en.md")
+	if err := os.WriteFile(source, []byte("---\ntitle: 标题\nslug: \"old-slug\"\n---\n正文\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(translation, []byte("---\ntitle: Title\nslug: \"old-slug\"\naliases:\n  - /en/older/\n---\nBody\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// 日文译文已使用新 slug，不应再加别名
+	japanese := filepath.Join(dir, "post.ja.md")
+	if err := os.WriteFile(japanese, []byte("---\ntitle: タイトル\nslug: \"new-slug\"\n---\n本文\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// 法文译文的 slug 由编辑单独设置，默认不应被覆盖
+	french := filepath.Join(dir, "post.fr.md")
+	if err := os.WriteFile(french, []byte("---\ntitle: Titre\nslug: \"titre-choisi\"\n---\nCorps\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// 旧 slug 由本工具生成，未被人工修改
+	if err := manifest.Record(source, manifest.KindSlug, "old-slug"); err != nil {
+		t.Fatal(err)
+	}
+
+	generator := NewArticleSlugGenerator(dir)
+	preview := ArticleSlugPreview{FilePath: source, Title: "标题", CurrentSlug: "old-slug", NewSlug: "new-slug", Status: "update", Published: true, Locked: true}
+	if err := generator.processTargetPreviews([]ArticleSlugPreview{preview}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(source); !strings.Contains(string(data), `slug: "old-slug"`) {
+		t.Fatalf("未强制时不应修改已发布文章的 slug:\n%s", data)
+	}
+
+	if modified := generator.ModifiedSlugs([]ArticleSlugPreview{preview}); len(modified) != 1 || modified[0] != french {
+		t.Fatalf("应列出 slug 被手动设置的译文: %v", modified)
+	}
+	generator.SetForceLocked(true)
+	if err := generator.processTargetPreviews([]ArticleSlugPreview{preview}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(source); string(data) != "---\ntitle: 标题\nslug: \"new-slug\"\naliases: [\"old-slug\"]\n---\n正文\n" {
+		t.Fatalf("源文件:\n%s", data)
+	}
+	if data, _ := os.ReadFile(translation); string(data) != "---\ntitle: Title\nslug: \"new-slug\"\naliases:\n  - /en/older/\n  - \"old-slug\"\n---\nBody\n" {
+		t.Fatalf("译文:\n%s", data)
+	}
+	if data, _ := os.ReadFile(japanese); string(data) != "---\ntitle: タイトル\nslug: \"new-slug\"\n---\n本文\n" {
+		t.Fatalf("slug 未改变的译文不应修改:\n%s", data)
+	}
+	if data, _ := os.ReadFile(french); string(data) != "---\ntitle: Titre\nslug: \"titre-choisi\"\n---\nCorps\n" {
+		t.Fatalf("手动设置的译文 slug 默认不应覆盖:\n%s", data)
+	}
+
+	// 译文 slug 已记录为 new-slug，之后再改 slug 时按生成结果同步
+	if manifest.Modified(translation, manifest.KindSlug, "new-slug") {
+		t.Fatal("同步后应记录译文的 slug")
+	}
+	if _, err := updateTranslationSlug(french, "new-slug", "newer-slug", false, manifest.PolicyOverwrite); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(french); !strings.Contains(string(data), `slug: "newer-slug"`) {
+		t.Fatalf("选择覆盖时应写入:\n%s", data)
+	}
+}
+
+func TestPinyinEngineWorksOfflineAndAsFallback(t *testing.T) {
+	previous := config.GetGlobalConfig()
+	defer config.SetGlobalConfig(previous)
+	cfg := *previous
+	cfg.Slug = config.SlugConfig{Engine: config.SlugEngineLLM, PinyinFallback: true, MaxWords: 8, MaxLength: 60}
+	config.SetGlobalConfig(&cfg)
+
+	engine, err := slugEngineFor(config.SlugEngineLLM, false)
+	if err != nil || engine != config.SlugEnginePinyin {
+		t.Fatalf("模型不可用时应回退到拼音: %q %v", engine, err)
+	}
+	offline := func([]string) (map[string]string, error) { return nil, errors.New("不应调用模型") }
+	slugs, err := generateSlugs([]string{"重庆的成长故事", "Go 调度器"}, engine, offline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slugs["重庆的成长故事"] != "chong-qing-de-cheng-zhang-gu-shi" || slugs["Go 调度器"] != "go-diao-du-qi" {
+		t.Fatalf("拼音 slug: %v", slugs)
+	}
+
+	cfg.Slug.PinyinFallback = false
+	if _, err := slugEngineFor(config.SlugEngineLLM, false); err == nil {
+		t.Fatal("关闭回退后模型不可用应返回错误")
+	}
+}
+
+func TestChooseSlugRejectsDuplicatesAndPinsChoice(t *testing.T) {
+	previous := config.GetGlobalConfig()
+	defer config.SetGlobalConfig(previous)
+	cfg := *previous
+	cacheDir := t.TempDir()
+	cfg.Cache.TagFileName = filepath.Join(cacheDir, "tags.json")
+	cfg.Cache.ArticleFileName = filepath.Join(cacheDir, "slugs.json")
+	cfg.Cache.CategoryFileName = filepath.Join(cacheDir, "categories.json")
+	cfg.Slug = config.SlugConfig{Engine: config.SlugEnginePinyin, Lock: config.SlugLockPublished, MaxWords: 8, MaxLength: 60}
+	config.SetGlobalConfig(&cfg)
+
+	dir := filepath.Join(t.TempDir(), "post")
+	generator := NewArticleSlugGenerator(dir)
+	mine := filepath.Join(dir, "a", "index.md")
+	other := filepath.Join(dir, "b", "index.md")
+	generator.owners = map[string]map[string]string{articleSection(generator.contentRoot, mine): {"go-diao-du-qi": mine, "go-scheduler": other}}
+	preview := ArticleSlugPreview{FilePath: mine, Title: "Go 调度器", CurrentSlug: "old-slug", NewSlug: "go-diao-du-qi", Status: "update", Published: true, Locked: true}
+
+	candidates := generator.SlugCandidates(preview)
+	if len(candidates) != 2 || candidates[0].Slug != "go-diao-du-qi" || candidates[0].Source != "预览" || candidates[1].Slug != "old-slug" {
+		t.Fatalf("候选: %+v", candidates)
+	}
+
+	if err := generator.ChooseSlug(&preview, "go-scheduler"); err == nil || !strings.Contains(err.Error(), other) {
+		t.Fatalf("与其他文章重复的 slug 应被拒绝: %v", err)
+	}
+	if err := generator.ChooseSlug(&preview, "Inside the Go Scheduler"); err != nil {
+		t.Fatal(err)
+	}
+	if preview.NewSlug != "inside-the-go-scheduler" || preview.Status != "update" || !preview.Locked {
+		t.Fatalf("选定后的预览: %+v", preview)
+	}
+	if generator.slugCollision(other, "go-diao-du-qi") != "" {
+		t.Fatal("放弃的 slug 应可被其他文章使用")
+	}
+	if pinned := NewArticleSlugGenerator(dir).translationUtils.PinnedArticleSlugs([]string{mine, other}); len(pinned) != 1 || pinned[mine] != "inside-the-go-scheduler" {
+		t.Fatalf("选定的 slug 应按文章固定到缓存: %v", pinned)
+	}
+
+	if err := generator.ChooseSlug(&preview, "old-slug"); err != nil || preview.Status != "skip" || preview.Locked {
+		t.Fatalf("选择当前 slug 后无需处理: %+v %v", preview, err)
+	}
+}
+
+func TestArticleSectionUsesContentRootInsteadOfTagsDir(t *testing.T) {
+	site := t.TempDir()
+	content := filepath.Join(site, "content")
+	article := filepath.Join(content, "post", "a", "index.md")
+	for _, tc := range []struct {
+		name       string
+		paths      config.PathsConfig
+		contentDir string
+	}{
+		{"标签目录在其他位置", config.PathsConfig{TagsDir: filepath.Join(site, "taxonomies", "tags")}, filepath.Join(content, "post")},
+		{"相对路径的默认标签目录", config.PathsConfig{TagsDir: "../tags"}, filepath.Join(content, "post")},
+		{"内容目录即内容根目录", config.PathsConfig{TagsDir: filepath.Join(content, "tags")}, content},
+		{"显式的源语言内容根目录", config.PathsConfig{TagsDir: "../tags", SourceContentRoot: content}, filepath.Join(content, "post", "a")},
+	} {
+		root := contentRoot(&config.Config{Paths: tc.paths}, tc.contentDir)
+		if section := articleSection(root, article); section != "post" {
+			t.Errorf("%s: root=%s section=%q", tc.name, root, section)
+		}
+	}
+	if section := articleSection(content, filepath.Join(site, "other", "index.md")); section != "" {
+		t.Fatalf("内容根目录之外的文章不属于任何栏目: %q", section)
+	}
+}
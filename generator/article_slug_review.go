@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"hugo-content-suite/config"
+	"hugo-content-suite/models"
+	"hugo-content-suite/utils"
+)
+
+// SlugCandidate 是挑选文章 slug 时的一个候选
+type SlugCandidate struct {
+	Slug      string
+	Source    string   // 预览、模型、模型原文、拼音或当前
+	Problems  []string // 不符合 slug 规范之处
+	Collision string   // 与同一栏目中其他文章重复时的说明，这样的候选不能选用
+}
+
+// slugOwners 记录内容根目录 root 的每个栏目中已被使用的 slug：不参与本次处理的文章使用现有 slug，
+// 参与处理的文章使用去重后的新 slug
+func slugOwners(root string, all, articles []models.Article, resolved []slugResolution) map[string]map[string]string {
+	owners := make(map[string]map[string]string)
+	claim := func(path, slug string) {
+		section := articleSection(root, path)
+		if owners[section] == nil {
+			owners[section] = make(map[string]string)
+		}
+		owners[section][slug] = path
+	}
+
+	processing := make(map[string]bool, len(articles))
+	for i, article := range articles {
+		processing[article.FilePath] = true
+		claim(article.FilePath, resolved[i].Slug)
+	}
+	for _, article := range all {
+		if !processing[article.FilePath] && article.Slug != "" {
+			claim(article.FilePath, article.Slug)
+		}
+	}
+	return owners
+}
+
+// slugCollision 返回 slug 被同一栏目中其他文章使用时的说明，未被使用时为空
+func (g *ArticleSlugGenerator) slugCollision(path, slug string) string {
+	if owner := g.owners[articleSection(g.contentRoot, path)][slug]; owner != "" && owner != path {
+		return fmt.Sprintf("%q 与 %s 重复", slug, owner)
+	}
+	return ""
+}
+
+// modelAvailable 报告模型服务是否可用，预处理时未测试（使用拼音）则在第一次需要时测试
+func (g *ArticleSlugGenerator) modelAvailable() bool {
+	if !g.modelChecked {
+		g.modelChecked, g.modelReady = true, connectModel(g.translationUtils)
+	}
+	return g.modelReady
+}
+
+// SlugCandidates 返回文章可选的 slug：预览中的 slug、模型给出的 slug.candidates 个候选
+// （先列按规范整理后的结果，整理前不同的原文列在其后）、拼音与当前 slug，重复的只保留第一个
+func (g *ArticleSlugGenerator) SlugCandidates(preview ArticleSlugPreview) []SlugCandidate {
+	policy := config.GetGlobalConfig().Slug
+	pinyin := pinyinSlug(preview.Title)
+
+	var candidates []SlugCandidate
+	seen := make(map[string]bool)
+	add := func(slug, source string) {
+		if slug == "" || seen[slug] {
+			return
+		}
+		seen[slug] = true
+		lang := SlugLanguage(slug, preview.Title)
+		candidates = append(candidates, SlugCandidate{
+			Slug:      slug,
+			Source:    source,
+			Problems:  policy.Violations(slug, lang),
+			Collision: g.slugCollision(preview.FilePath, slug),
+		})
+	}
+
+	add(preview.NewSlug, "预览")
+	if policy.Candidates > 0 && g.modelAvailable() {
+		suggestions, err := g.translationUtils.SuggestArticleSlugs(preview.Title, policy.Candidates)
+		if err != nil {
+			fmt.Printf("⚠️ 获取候选 slug 失败: %v\n", err)
+		}
+		var originals []string
+		for _, suggestion := range suggestions {
+			original := utils.FormatSlugField(suggestion)
+			add(policy.Apply(original, translatedSlugLanguage), "模型")
+			originals = append(originals, original)
+		}
+		for _, original := range originals {
+			add(original, "模型原文")
+		}
+	}
+	add(pinyin, "拼音")
+	add(preview.CurrentSlug, "当前")
+	return candidates
+}
+
+// ChooseSlug 把 slug 设为文章的新 slug 并按文章文件固定到 slug 缓存，之后再生成时沿用。
+// slug 先格式化为小写字母、数字与连字符；为空或与同一栏目中其他文章重复时返回错误。
+// 选择当前 slug 时预览变为无需处理
+func (g *ArticleSlugGenerator) ChooseSlug(preview *ArticleSlugPreview, slug string) error {
+	slug = utils.FormatSlugField(slug)
+	if slug == "" {
+		return errors.New("slug 为空")
+	}
+	if collision := g.slugCollision(preview.FilePath, slug); collision != "" {
+		return fmt.Errorf("slug %s", collision)
+	}
+
+	section := articleSection(g.contentRoot, preview.FilePath)
+	if g.owners == nil {
+		g.owners = make(map[string]map[string]string)
+	}
+	if g.owners[section] == nil {
+		g.owners[section] = make(map[string]string)
+	}
+	if g.owners[section][preview.NewSlug] == preview.FilePath {
+		delete(g.owners[section], preview.NewSlug)
+	}
+	g.owners[section][slug] = preview.FilePath
+
+	preview.NewSlug = slug
+	preview.Collision = ""
+	switch {
+	case preview.CurrentSlug == "":
+		preview.Status = "missing"
+	case preview.CurrentSlug != slug:
+		preview.Status = "update"
+	default:
+		preview.Status = "skip"
+	}
+	preview.Locked = preview.Status == "update" && preview.Published && config.GetGlobalConfig().Slug.LockPublished()
+	return g.translationUtils.PinArticleSlug(preview.FilePath, slug)
+}
@@ -1,13 +1,18 @@
 package generator
 
 import (
+	"errors"
 	"fmt"
 	"hugo-content-suite/config"
+	"hugo-content-suite/frontmatter"
+	"hugo-content-suite/layout"
+	"hugo-content-suite/manifest"
 	"hugo-content-suite/models"
 	"hugo-content-suite/scanner"
 	"hugo-content-suite/translator"
 	"hugo-content-suite/utils"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -15,29 +20,33 @@ import (
 // ArticleTranslator 文章翻译器
 type ArticleTranslator struct {
 	contentDir       string
+	layout           layout.Layout
 	translationUtils *translator.TranslationUtils
 	contentParser    *ContentParser
+	filter           models.ArticleFilter
+	conflictPolicy   manifest.Policy
+	skipped          int
 }
 
-// TranslationStatus 翻译状态信息
-type TranslationStatus struct {
-	MissingArticles  int // 有缺失翻译的文章数
-	ExistingArticles int // 所有翻译都存在的文章数
-	TotalArticles    int // 文章总数
-}
+// 译文状态：缺失、源文件已修改后过期、与源文件一致
+const (
+	TranslationMissing = "missing"
+	TranslationStale   = "stale"
+	TranslationCurrent = "current"
+)
 
 // ArticleTranslationPreview 文章翻译预览信息
 type ArticleTranslationPreview struct {
 	Article      models.Article
 	TargetLang   string
 	TargetFile   string
-	Status       string // "missing", "update", "skip"
+	Status       string // TranslationMissing、TranslationStale 或 TranslationCurrent
 	LanguageName string
 }
 
 // 实现 StatusLike 接口
 func (a ArticleTranslationPreview) GetStatus() string {
-	if a.Status == "missing" {
+	if a.Status == TranslationMissing {
 		return "create"
 	}
 	return "update"
@@ -47,62 +56,36 @@ func (a ArticleTranslationPreview) GetStatus() string {
 func NewArticleTranslator(contentDir string) *ArticleTranslator {
 	return &ArticleTranslator{
 		contentDir:       contentDir,
+		layout:           layout.ForContentDir(contentDir),
 		translationUtils: translator.NewTranslationUtils(),
 		contentParser:    NewContentParser(),
 	}
 }
 
-// GetTranslationStatus 获取翻译状态统计
-func (a *ArticleTranslator) GetTranslationStatus() (*TranslationStatus, error) {
-	articles, err := scanner.ScanArticles(a.contentDir)
-	if err != nil {
-		return nil, fmt.Errorf("扫描文章失败: %v", err)
-	}
-
-	cfg := config.GetGlobalConfig()
-	targetLanguages := cfg.Language.TargetLanguages
-
-	missingCount := 0
-	existingCount := 0
-	totalArticles := 0
-
-	for _, article := range articles {
-		if article.Title == "" {
-			continue
-		}
-		totalArticles++
-
-		hasMissing := false
-		hasExisting := false
+// SetFilter 设置筛选条件，之后的扫描只处理满足条件的文章
+func (a *ArticleTranslator) SetFilter(filter models.ArticleFilter) {
+	a.filter = filter
+}
 
-		// 检查每种目标语言的翻译状态
-		for _, targetLang := range targetLanguages {
-			targetFile := utils.BuildTargetFilePath(article.FilePath, targetLang)
-			if targetFile == "" {
-				continue
-			}
+// SkippedFiles 返回上次扫描中 front matter 无法解析而跳过的文件数
+func (a *ArticleTranslator) SkippedFiles() int {
+	return a.skipped
+}
 
-			if utils.FileExists(targetFile) {
-				hasExisting = true
-			} else {
-				hasMissing = true
-			}
-		}
+// SetConflictPolicy 设置译文在上次生成后被手动修改时的处理方式，默认跳过
+func (a *ArticleTranslator) SetConflictPolicy(policy manifest.Policy) {
+	a.conflictPolicy = policy
+}
 
-		// 如果有任何语言缺失翻译，则算作需要翻译的文章
-		if hasMissing {
-			missingCount++
-		} else if hasExisting {
-			// 只有当所有语言都存在时，才算作已翻译的文章
-			existingCount++
+// ModifiedTargets 返回预览中上次生成后被手动修改过的译文文件
+func (a *ArticleTranslator) ModifiedTargets(previews []ArticleTranslationPreview) []string {
+	var modified []string
+	for _, preview := range previews {
+		if preview.Status != TranslationMissing && manifest.FileModified(preview.TargetFile, manifest.KindTranslation) {
+			modified = append(modified, preview.TargetFile)
 		}
 	}
-
-	return &TranslationStatus{
-		MissingArticles:  missingCount,
-		ExistingArticles: existingCount,
-		TotalArticles:    totalArticles,
-	}, nil
+	return modified
 }
 
 // PrepareArticleTranslations 预处理文章翻译
@@ -119,11 +102,15 @@ func (a *ArticleTranslator) PrepareArticleTranslations() ([]ArticleTranslationPr
 		fmt.Println("✅ 成功")
 	}
 
-	// 获取所有文章，使用翻译扫描函数读取完整内容
-	articles, err := scanner.ScanArticlesForTranslation(a.contentDir)
+	// 源文件与译文一次扫描；预览只需要元数据和缓存的字符数，正文在翻译时按需读取。
+	// 译文记录的 translationSourceHash 随扫描索引缓存，判断是否过期时无需逐个读取译文
+	scanned, issues, err := scanner.ScanArticlesWithIssues(a.contentDir, true)
 	if err != nil {
 		return nil, 0, 0, fmt.Errorf("扫描文章失败: %v", err)
 	}
+	a.skipped = scanner.Skipped(issues)
+	articles, stamped := splitTranslations(scanned)
+	articles = a.filter.Apply(articles, a.contentDir)
 
 	cfg := config.GetGlobalConfig()
 	targetLanguages := cfg.Language.TargetLanguages
@@ -143,30 +130,32 @@ func (a *ArticleTranslator) PrepareArticleTranslations() ([]ArticleTranslationPr
 
 	createCount := 0
 	updateCount := 0
+	staleTasks := 0
 
 	for i, article := range validArticles {
 		fmt.Printf("  [%d/%d] 检查: %s", i+1, len(validArticles), article.Title)
 
 		articleHasMissing := false
+		articleHasStale := false
 		articleHasExisting := false
 
 		for _, targetLang := range targetLanguages {
-			targetFile := utils.BuildTargetFilePath(article.FilePath, targetLang)
+			targetFile := a.layout.TranslationPath(article.FilePath, targetLang)
 			if targetFile == "" {
 				continue
 			}
 
-			targetLangName := cfg.Language.LanguageNames[targetLang]
-			if targetLangName == "" {
-				targetLangName = targetLang
-			}
+			targetLangName := cfg.Language.DisplayName(targetLang)
 
-			var status string
-			if !utils.FileExists(targetFile) {
-				status = "missing"
+			status := translationState(article, targetFile, stamped)
+			switch status {
+			case TranslationMissing:
 				articleHasMissing = true
-			} else {
-				status = "update"
+			case TranslationStale:
+				articleHasStale = true
+				articleHasExisting = true
+				staleTasks++
+			default:
 				articleHasExisting = true
 			}
 
@@ -193,6 +182,8 @@ func (a *ArticleTranslator) PrepareArticleTranslations() ([]ArticleTranslationPr
 			statusText = " 🔄 部分翻译"
 		} else if articleHasMissing {
 			statusText = " ✨ 需要翻译"
+		} else if articleHasStale {
+			statusText = " ⚠️ 译文过期"
 		} else {
 			statusText = " ✅ 已完全翻译"
 		}
@@ -202,6 +193,7 @@ func (a *ArticleTranslator) PrepareArticleTranslations() ([]ArticleTranslationPr
 	fmt.Printf("\n📈 统计结果:\n")
 	fmt.Printf("   ✨ 有缺失翻译的文章: %d 篇\n", createCount)
 	fmt.Printf("   🔄 已有翻译的文章: %d 篇\n", updateCount)
+	fmt.Printf("   ⚠️ 源文件修改后过期的译文: %d 个\n", staleTasks)
 	fmt.Printf("   📦 总计: %d 篇文章，%d 个翻译任务\n", len(validArticles), len(previews))
 
 	return previews, createCount, updateCount, nil
@@ -265,7 +257,9 @@ func (a *ArticleTranslator) processTargetPreviews(targetPreviews []ArticleTransl
 				preview.Article, preview.TargetFile, preview.TargetLang,
 				totalCharsAllArticles, &globalTranslatedChars, startTime,
 				remainingArticles, remainingLangsOfCurrentArticle-1,
-			); err != nil {
+			); errors.Is(err, manifest.ErrModified) {
+				fmt.Printf("     ⏭️  %v\n", err)
+			} else if err != nil {
 				fmt.Printf("     ❌ 翻译失败: %v\n", err)
 				articleErrorCount++
 				totalErrorCount++
@@ -309,219 +303,212 @@ func (a *ArticleTranslator) groupPreviewsByArticle(previews []ArticleTranslation
 	return groups
 }
 
-// TranslateArticles 翻译文章到多种语言
-func (a *ArticleTranslator) TranslateArticles(mode string) error {
-	cfg := config.GetGlobalConfig()
-	targetLanguages := cfg.Language.TargetLanguages
-
-	utils.LogOperation("开始多语言翻译", map[string]interface{}{
-		"mode":             mode,
-		"target_languages": targetLanguages,
-		"content_dir":      a.contentDir,
-	})
+// translateSingleArticleToLanguage 翻译单篇文章到指定语言
+func (a *ArticleTranslator) translateSingleArticleToLanguage(
+	article models.Article, targetFile, targetLang string,
+	totalCharsAllArticles int, globalTranslatedChars *int, globalStartTime time.Time,
+	remainingArticles int, remainingLangsOfCurrentArticle int,
+) error {
+	utils.Info("开始翻译文章到 %s: %s", targetLang, article.FilePath)
 
-	// 获取所有文章，使用新的扫描函数读取完整内容
-	articles, err := scanner.ScanArticlesForTranslation(a.contentDir)
-	if err != nil {
-		utils.ErrorWithFields("扫描文章失败", map[string]interface{}{
-			"content_dir": a.contentDir,
-			"error":       err.Error(),
-		})
-		return fmt.Errorf("扫描文章失败: %v", err)
+	// 扫描结果只含元数据，翻译前读取前置信息和正文内容
+	if !scanner.HasContent(&article) {
+		if err := scanner.LoadContent(&article); err != nil {
+			return err
+		}
 	}
 
-	var targetArticles []models.Article
-	for _, article := range articles {
-		if article.Title == "" {
-			continue
+	// 人工修改过的译文默认保留，提前返回以免白白调用模型
+	if manifest.FileModified(targetFile, manifest.KindTranslation) {
+		switch a.conflictPolicy {
+		case manifest.PolicyOverwrite, manifest.PolicySaveNew:
+		case manifest.PolicyMerge:
+			return a.mergeTranslation(article, targetFile, targetLang,
+				totalCharsAllArticles, globalTranslatedChars, globalStartTime,
+				remainingArticles, remainingLangsOfCurrentArticle)
+		default:
+			return manifest.ErrModified
 		}
-		targetArticles = append(targetArticles, article)
 	}
-
-	if len(targetArticles) == 0 {
-		fmt.Printf("没有需要翻译的文章\n")
-		return nil
+	frontMatter := article.FrontMatter
+	bodyBlocks := article.BodyBlocks
+	var existing *frontmatter.Document
+	if doc, err := frontmatter.ReadFile(targetFile); err == nil {
+		existing = doc
 	}
 
-	// 测试连接
-	if err := a.translationUtils.TestConnection(); err != nil {
-		utils.ErrorWithFields("LM Studio连接失败", map[string]interface{}{
-			"error": err.Error(),
-		})
-		return fmt.Errorf("无法连接到LM Studio: %v", err)
+	// 翻译前置数据和正文
+	translatedFrontMatter, err := a.translateFrontMatterToLanguage(frontMatter, article.FrontMatterFormat, targetLang, article.SourceHash, existing)
+	if err != nil {
+		fmt.Printf("⚠️ 翻译前置数据失败: %v\n", err)
+		return fmt.Errorf("翻译前置数据失败: %v", err)
 	}
-	fmt.Printf("LM Studio连接成功！\n")
-
-	return a.processArticlesByLanguage(targetArticles, targetLanguages, mode)
-}
-
-// processArticlesByLanguage 按语言处理文章
-func (a *ArticleTranslator) processArticlesByLanguage(targetArticles []models.Article, targetLanguages []string, mode string) error {
-	totalSuccessCount := 0
-	totalErrorCount := 0
 
-	// 1. 统计所有需要翻译的正文总字符数 - 直接使用缓存的字符数
-	totalCharsAllArticles := 0
-	for _, article := range targetArticles {
-		for _, targetLang := range targetLanguages {
-			targetFile := utils.BuildTargetFilePath(article.FilePath, targetLang)
-			if targetFile == "" {
-				continue
-			}
-			shouldTranslate := a.shouldTranslateArticle(targetFile, mode)
-			if shouldTranslate {
-				totalCharsAllArticles += article.CharCount
-			}
-		}
+	translatedBlocks, err := a.translateArticleBodyParagraphsWithProgress(
+		bodyBlocks, targetLang, totalCharsAllArticles, globalTranslatedChars, globalStartTime,
+		remainingArticles, remainingLangsOfCurrentArticle,
+	)
+	if err != nil {
+		return fmt.Errorf("翻译正文失败: %v", err)
 	}
+	translatedBody := strings.Join(translatedBlocks, "\n\n")
 
-	globalTranslatedChars := 0
-	startTime := time.Now()
-
-	// 按文章顺序翻译，每篇文章完成所有语言后再处理下一篇
-	for i, article := range targetArticles {
-		fmt.Printf("\n📄 处理文章 (%d/%d): %s\n", i+1, len(targetArticles), article.Title)
-
-		articleSuccessCount := 0
-		articleErrorCount := 0
-
-		// 统计当前文章剩余语言数
-		remainingLangsOfCurrentArticle := 0
-		for _, targetLang := range targetLanguages {
-			targetFile := utils.BuildTargetFilePath(article.FilePath, targetLang)
-			if targetFile == "" {
-				continue
-			}
-			shouldTranslate := a.shouldTranslateArticle(targetFile, mode)
-			if shouldTranslate {
-				remainingLangsOfCurrentArticle++
-			}
-		}
-
-		cfg := config.GetGlobalConfig()
-		for langIndex, targetLang := range targetLanguages {
-			targetLangName := cfg.Language.LanguageNames[targetLang]
-			if targetLangName == "" {
-				targetLangName = targetLang
-			}
-
-			targetFile := utils.BuildTargetFilePath(article.FilePath, targetLang)
-			if targetFile == "" {
-				continue
-			}
-			shouldTranslate := a.shouldTranslateArticle(targetFile, mode)
-			if !shouldTranslate {
-				fmt.Printf("  ⏭️  跳过 %s (已存在)\n", targetLangName)
-				continue
-			}
-
-			// 统计全局剩余文章数
-			remainingArticles := 0
-			for j := i + 1; j < len(targetArticles); j++ {
-				for _, tl := range targetLanguages {
-					tf := utils.BuildTargetFilePath(targetArticles[j].FilePath, tl)
-					if tf == "" {
-						continue
-					}
-					if a.shouldTranslateArticle(tf, mode) {
-						remainingArticles++
-						break
-					}
-				}
-			}
-
-			fmt.Printf("  🌐 翻译为 %s (%d/%d)\n", targetLangName, langIndex+1, len(targetLanguages))
-			fmt.Printf("     目标文件: %s\n", targetFile)
-
-			if err := a.translateSingleArticleToLanguage(
-				article, targetFile, targetLang,
-				totalCharsAllArticles, &globalTranslatedChars, startTime,
-				remainingArticles, remainingLangsOfCurrentArticle-1,
-			); err != nil {
-				fmt.Printf("     ❌ 翻译失败: %v\n", err)
-				articleErrorCount++
-				totalErrorCount++
-			} else {
-				fmt.Printf("     ✅ 翻译完成\n")
-				articleSuccessCount++
-				totalSuccessCount++
-			}
-			remainingLangsOfCurrentArticle--
-		}
-
-		fmt.Printf("  📊 当前文章翻译结果: 成功 %d, 失败 %d\n", articleSuccessCount, articleErrorCount)
+	// 合成并写入最终内容，换行风格与 BOM 跟随源文件
+	source := &frontmatter.Document{LineEnding: article.LineEnding, BOM: article.BOM}
+	finalContent := source.Restore(a.contentParser.CombineTranslatedContent(translatedFrontMatter, translatedBody))
+	writtenFile, err := manifest.WriteFile(targetFile, manifest.KindTranslation, finalContent, a.conflictPolicy)
+	if err != nil {
+		return fmt.Errorf("写入目标文件失败: %w", err)
+	}
+	if writtenFile != targetFile {
+		fmt.Printf("     📄 译文已被手动修改，新结果另存为: %s\n", writtenFile)
+	} else if err := manifest.SaveSnapshot(targetFile, manifest.Snapshot{
+		Source: article.BodyContent, Translated: translatedBlocks, Fields: translatedFieldValues(article),
+	}); err != nil {
+		fmt.Printf("     ⚠️ 保存译文快照失败，之后无法三方合并: %v\n", err)
 	}
 
-	fmt.Printf("\n🎉 多语言翻译全部完成！\n")
-	fmt.Printf("- 目标语言: %v\n", targetLanguages)
-	fmt.Printf("- 总成功翻译: %d 篇\n", totalSuccessCount)
-	fmt.Printf("- 总翻译失败: %d 篇\n", totalErrorCount)
-
+	utils.Info("文章翻译完成 (%s): %s", targetLang, writtenFile)
 	return nil
 }
 
-// translateSingleArticleToLanguage 翻译单篇文章到指定语言
-func (a *ArticleTranslator) translateSingleArticleToLanguage(
+// mergeTranslation 在译文被手动修改后合并源文的变化：只重新翻译快照中没有的源文块，
+// 再以快照译文为共同祖先与现有译文三方合并，两边都改动的区段用冲突标记标出；
+// 前置数据与同步前置数据相同，只重新翻译源文本变化的字段。
+// 合并结果包含人工内容，因此不更新生成清单，之后仍按手动修改处理
+func (a *ArticleTranslator) mergeTranslation(
 	article models.Article, targetFile, targetLang string,
 	totalCharsAllArticles int, globalTranslatedChars *int, globalStartTime time.Time,
 	remainingArticles int, remainingLangsOfCurrentArticle int,
 ) error {
-	utils.Info("开始翻译文章到 %s: %s", targetLang, article.FilePath)
-
-	// 直接使用缓存的前置信息和正文内容
-	frontMatter := article.FrontMatter
-	bodyParagraphs := article.BodyContent
-
-	// 翻译前置数据和正文
-	translatedFrontMatter, err := a.translateFrontMatterToLanguage(frontMatter, targetLang)
+	snapshot, ok := manifest.LoadSnapshot(targetFile)
+	if !ok || snapshot.FieldsOnly {
+		return fmt.Errorf("%w（没有生成快照，无法合并）", manifest.ErrModified)
+	}
+	doc, err := frontmatter.ReadFile(targetFile)
 	if err != nil {
-		fmt.Printf("⚠️ 翻译前置数据失败: %v\n", err)
-		return fmt.Errorf("翻译前置数据失败: %v", err)
+		return fmt.Errorf("读取现有译文失败: %v", err)
 	}
 
-	translatedBody, err := a.translateArticleBodyParagraphsWithProgress(
-		bodyParagraphs, targetLang, totalCharsAllArticles, globalTranslatedChars, globalStartTime,
-		remainingArticles, remainingLangsOfCurrentArticle,
-	)
-	if err != nil {
-		return fmt.Errorf("翻译正文失败: %v", err)
+	// 源文未变的块沿用快照中的译文
+	previous := make(map[string]string, len(snapshot.Source))
+	for i, text := range snapshot.Source {
+		if _, exists := previous[text]; !exists {
+			previous[text] = snapshot.Translated[i]
+		}
+	}
+	translatedBlocks := make([]string, len(article.BodyBlocks))
+	var changedBlocks []models.Block
+	var changedIndexes []int
+	for i, block := range article.BodyBlocks {
+		if translated, ok := previous[block.Text]; ok {
+			translatedBlocks[i] = translated
+			continue
+		}
+		changedBlocks = append(changedBlocks, block)
+		changedIndexes = append(changedIndexes, i)
+	}
+	fmt.Printf("🔀 三方合并: 源文 %d 块中 %d 块有变化需要重新翻译\n", len(article.BodyBlocks), len(changedBlocks))
+
+	if len(changedBlocks) > 0 {
+		translated, err := a.translateArticleBodyParagraphsWithProgress(
+			changedBlocks, targetLang, totalCharsAllArticles, globalTranslatedChars, globalStartTime,
+			remainingArticles, remainingLangsOfCurrentArticle,
+		)
+		if err != nil {
+			return fmt.Errorf("翻译正文失败: %v", err)
+		}
+		for i, index := range changedIndexes {
+			translatedBlocks[index] = translated[i]
+		}
 	}
 
-	// 合成并写入最终内容
-	finalContent := a.contentParser.CombineTranslatedContent(translatedFrontMatter, translatedBody)
-	if err := utils.WriteFileContent(targetFile, finalContent); err != nil {
+	// 三方都按同一规则重新分块，避免模型输出中的空行让块边界错位
+	base := blockTexts(scanner.SegmentBody(strings.Join(snapshot.Translated, "\n\n")))
+	theirs := blockTexts(scanner.SegmentBody(strings.Join(translatedBlocks, "\n\n")))
+	ours := blockTexts(scanner.SegmentBody(doc.Body))
+	merged, conflicts := mergeBlocks(base, ours, theirs)
+
+	leading := doc.Body[:len(doc.Body)-len(strings.TrimLeft(doc.Body, "\n"))]
+	trailing := doc.Body[len(strings.TrimRight(doc.Body, "\n")):]
+	doc.Body = leading + strings.Join(merged, "\n\n") + trailing
+
+	// 前置数据按同步规则更新；有字段缺少翻译记录时无法确认已同步，不更新源文件哈希
+	stampedHash, _, _ := doc.GetString(models.TranslationSourceHashKey)
+	fields := make(map[string]string, len(snapshot.Fields))
+	for key, value := range snapshot.Fields {
+		fields[key] = value
+	}
+	if snapshot.Fields == nil && stampedHash == article.SourceHash {
+		fields = translatedFieldValues(article)
+	}
+	if doc.Format != frontmatter.FormatNone {
+		source := &frontmatter.Document{Format: frontmatter.Format(article.FrontMatterFormat), FrontMatter: article.FrontMatter}
+		changes, untracked, err := a.syncFields(doc, source, fields, targetLang)
+		if err != nil {
+			return err
+		}
+		if len(changes) > 0 {
+			fmt.Printf("     🔁 同步前置数据: %s\n", strings.Join(changes, ", "))
+		}
+		if len(untracked) > 0 {
+			fmt.Printf("     ℹ️  %s 缺少翻译记录，未同步，译文仍显示为过期\n", strings.Join(untracked, ", "))
+		} else if err := doc.SetString(models.TranslationSourceHashKey, article.SourceHash); err != nil {
+			return fmt.Errorf("记录源文件哈希失败: %v", err)
+		}
+	}
+	if err := utils.WriteFileContent(targetFile, doc.String()); err != nil {
 		return fmt.Errorf("写入目标文件失败: %v", err)
 	}
+	if err := manifest.SaveSnapshot(targetFile, manifest.Snapshot{
+		Source: article.BodyContent, Translated: translatedBlocks, Fields: fields,
+	}); err != nil {
+		fmt.Printf("     ⚠️ 保存译文快照失败: %v\n", err)
+	}
 
-	utils.Info("文章翻译完成 (%s): %s", targetLang, targetFile)
+	if conflicts > 0 {
+		fmt.Printf("     ⚠️ 合并产生 %d 处冲突，已用 %q 标记，请人工处理\n", conflicts, conflictOursMarker)
+	}
+	utils.Info("译文三方合并完成 (%s): %s，冲突 %d 处", targetLang, targetFile, conflicts)
 	return nil
 }
 
-// translateArticleBodyParagraphsWithProgress 翻译段落数组
+func blockTexts(blocks []models.Block) []string {
+	texts := make([]string, len(blocks))
+	for i, block := range blocks {
+		texts[i] = block.Text
+	}
+	return texts
+}
+
+// translateArticleBodyParagraphsWithProgress 按块翻译正文，返回与输入一一对应的译文：
+// 原样块不调用模型，表格只翻译单元格，其余块整体翻译
 func (a *ArticleTranslator) translateArticleBodyParagraphsWithProgress(
-	paragraphs []string, targetLang string,
+	blocks []models.Block, targetLang string,
 	totalCharsAllArticles int, globalTranslatedChars *int, globalStartTime time.Time,
 	remainingArticles int, remainingLangsOfCurrentArticle int,
-) (string, error) {
-	if len(paragraphs) == 0 {
-		return "", nil
+) ([]string, error) {
+	if len(blocks) == 0 {
+		return nil, nil
 	}
 
 	cfg := config.GetGlobalConfig()
-	targetLangName := cfg.Language.LanguageNames[targetLang]
-	if targetLangName == "" {
-		targetLangName = targetLang
-	}
+	targetLangName := cfg.Language.DisplayName(targetLang)
 
 	fmt.Printf("\n翻译正文到 %s...\n", targetLangName)
 
-	// 应用段落拆分并获取映射关系
-	splitResult := a.contentParser.applySplittingWithMapping(paragraphs)
-
-	splitParagraphs := splitResult.Paragraphs
-	mappings := splitResult.Mappings
-	totalParagraphs := len(splitParagraphs)
-	translatableParagraphs := len(splitParagraphs)
+	// 收集需要交给模型的文本，记录每块对应的数量以便回填
+	var paragraphs []string
+	unitCounts := make([]int, len(blocks))
+	verbatimBlocks := 0
+	for i, block := range blocks {
+		units := a.contentParser.BlockUnits(block)
+		unitCounts[i] = len(units)
+		paragraphs = append(paragraphs, units...)
+		if block.Kind == models.BlockVerbatim {
+			verbatimBlocks++
+		}
+	}
 
 	// 统计总字符数
 	totalChars := 0
@@ -529,34 +516,78 @@ func (a *ArticleTranslator) translateArticleBodyParagraphsWithProgress(
 		totalChars += len([]rune(p))
 	}
 
-	fmt.Printf("📖 总段落数: %d | 需翻译: %d | 跳过: %d\n",
-		totalParagraphs, translatableParagraphs, totalParagraphs-translatableParagraphs)
+	fmt.Printf("📖 总块数: %d | 需翻译: %d | 原样保留: %d\n",
+		len(blocks), len(blocks)-verbatimBlocks, verbatimBlocks)
 	fmt.Printf("🔢 总字符数: %d\n", totalChars)
 
+	var translatedUnits []string
+	if len(paragraphs) > 0 {
+		var err error
+		translatedUnits, err = a.translateUnits(
+			paragraphs, targetLang, totalChars, totalCharsAllArticles, globalTranslatedChars, globalStartTime,
+			remainingArticles, remainingLangsOfCurrentArticle,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]string, len(blocks))
+	next := 0
+	for i, block := range blocks {
+		result[i] = a.contentParser.RebuildBlock(block, translatedUnits[next:next+unitCounts[i]])
+		next += unitCounts[i]
+	}
+	return result, nil
+}
+
+// translateUnits 对待翻译文本应用长段落拆分并翻译，返回与输入一一对应的译文
+func (a *ArticleTranslator) translateUnits(
+	paragraphs []string, targetLang string, totalChars int,
+	totalCharsAllArticles int, globalTranslatedChars *int, globalStartTime time.Time,
+	remainingArticles int, remainingLangsOfCurrentArticle int,
+) ([]string, error) {
+	cfg := config.GetGlobalConfig()
+
+	// 应用段落拆分并获取映射关系
+	splitResult := a.contentParser.applySplittingWithMapping(paragraphs)
+
 	// 翻译段落，传递全局进度参数
 	translatedParagraphs, err := a.translateParagraphsToLanguageWithMappingAndGlobalProgress(
-		splitParagraphs, targetLang, totalChars, totalCharsAllArticles, globalTranslatedChars, globalStartTime,
+		splitResult.Paragraphs, targetLang, totalChars, totalCharsAllArticles, globalTranslatedChars, globalStartTime,
 		remainingArticles, remainingLangsOfCurrentArticle,
 	)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// 如果启用了合并功能，则合并拆分后的段落
 	if cfg.Paragraph.MergeAfterTranslation {
 		fmt.Printf("🔄 合并拆分的段落...\n")
-		mergedParagraphs, err := a.contentParser.MergeTranslatedParagraphs(translatedParagraphs, mappings)
-		if err != nil {
-			fmt.Printf("⚠️ 段落合并失败，使用原始翻译结果: %v\n", err)
-			return strings.Join(translatedParagraphs, "\n\n"), nil
+		mergedParagraphs, err := a.contentParser.MergeTranslatedParagraphs(translatedParagraphs, splitResult.Mappings, targetLang)
+		if err == nil && len(mergedParagraphs) != len(paragraphs) {
+			err = fmt.Errorf("合并后得到 %d 段，应为 %d 段", len(mergedParagraphs), len(paragraphs))
 		}
-
-		fmt.Printf("✅ 段落合并完成: %d个翻译段落 → %d个合并段落\n",
-			len(translatedParagraphs), len(mergedParagraphs))
-		return strings.Join(mergedParagraphs, "\n\n"), nil
+		if err == nil {
+			fmt.Printf("✅ 段落合并完成: %d个翻译段落 → %d个合并段落\n",
+				len(translatedParagraphs), len(mergedParagraphs))
+			return mergedParagraphs, nil
+		}
+		fmt.Printf("⚠️ 段落合并失败，使用原始翻译结果: %v\n", err)
 	}
 
-	return strings.Join(translatedParagraphs, "\n\n"), nil
+	// 未合并时拆分的片段各自成段
+	grouped := make([]string, 0, len(paragraphs))
+	next := 0
+	for _, mapping := range splitResult.Mappings {
+		end := next + len(mapping.SplitParts)
+		if end > len(translatedParagraphs) {
+			end = len(translatedParagraphs)
+		}
+		grouped = append(grouped, strings.Join(translatedParagraphs[next:end], "\n\n"))
+		next = end
+	}
+	return grouped, nil
 }
 
 // 新增：带全局进度的段落翻译
@@ -687,11 +718,43 @@ func (a *ArticleTranslator) translateParagraphsToLanguageWithMappingAndGlobalPro
 	return translatedParagraphs, nil
 }
 
-// shouldTranslateArticle 判断是否应该翻译文章
-func (a *ArticleTranslator) shouldTranslateArticle(targetFile, mode string) bool {
-	if _, err := os.Stat(targetFile); os.IsNotExist(err) {
-		return mode == "missing" || mode == "all"
-	} else {
-		return mode == "update" || mode == "all"
+// translationState 比较译文记录的 translationSourceHash 与源文件当前哈希，stamped 为扫描得到的
+// 译文绝对路径 → translationSourceHash。未记录哈希的旧译文按修改时间判断，源文件较新即视为过期
+func translationState(article models.Article, targetFile string, stamped map[string]string) string {
+	targetInfo, err := os.Stat(targetFile)
+	if err != nil {
+		return TranslationMissing
+	}
+	if hash := stamped[absPath(targetFile)]; hash != "" {
+		if hash == article.SourceHash {
+			return TranslationCurrent
+		}
+		return TranslationStale
+	}
+	if sourceInfo, err := os.Stat(article.FilePath); err == nil && sourceInfo.ModTime().After(targetInfo.ModTime()) {
+		return TranslationStale
+	}
+	return TranslationCurrent
+}
+
+// splitTranslations 从包含译文的扫描结果中分出源文件，并返回译文绝对路径 → 记录的 translationSourceHash
+func splitTranslations(scanned []models.Article) ([]models.Article, map[string]string) {
+	var sources []models.Article
+	stamped := make(map[string]string)
+	for _, article := range scanned {
+		if article.Lang == "" {
+			sources = append(sources, article)
+		} else if article.TranslationSourceHash != "" {
+			stamped[absPath(article.FilePath)] = article.TranslationSourceHash
+		}
+	}
+	return sources, stamped
+}
+
+// absPath 返回 path 的绝对路径，失败时原样返回，用于比较扫描结果与布局推导出的路径
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
 	}
+	return path
 }
@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"hugo-content-suite/models"
+	"hugo-content-suite/scanner"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTranslationStateComparesSourceHash(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "index.md")
+	target := filepath.Join(dir, "index.en.md")
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	load := func() models.Article {
+		t.Helper()
+		article := models.Article{FilePath: source}
+		if err := scanner.LoadContent(&article); err != nil {
+			t.Fatal(err)
+		}
+		return article
+	}
+
+	// 译文的哈希来自扫描结果，与准备翻译时相同
+	state := func(article models.Article) string {
+		t.Helper()
+		scanned, err := scanner.ScanArticlesWithLangs(dir, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, stamped := splitTranslations(scanned)
+		return translationState(article, target, stamped)
+	}
+
+	write(source, "---\ntitle: 原文\n---\n正文\n")
+	article := load()
+	if state := state(article); state != TranslationMissing {
+		t.Fatalf("译文不存在时应为 missing，实际为 %s", state)
+	}
+
+	write(target, "---\ntitle: Source\ntranslationSourceHash: \""+article.SourceHash+"\"\n---\nBody\n")
+	if state := state(article); state != TranslationCurrent {
+		t.Fatalf("哈希一致时应为 current，实际为 %s", state)
+	}
+
+	write(source, "---\ntitle: 原文\r\n---\r\n正文\r\n")
+	if state := state(load()); state != TranslationCurrent {
+		t.Fatalf("只改换行风格不应使译文过期，实际为 %s", state)
+	}
+
+	write(source, "---\ntitle: 原文\n---\n修改后的正文\n")
+	if state := state(load()); state != TranslationStale {
+		t.Fatalf("源文件修改后应为 stale，实际为 %s", state)
+	}
+
+	// 未记录哈希的旧译文按修改时间判断
+	write(target, "---\ntitle: Source\n---\nBody\n")
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(source, past, past); err != nil {
+		t.Fatal(err)
+	}
+	if state := state(load()); state != TranslationCurrent {
+		t.Fatalf("译文比源文件新时应为 current，实际为 %s", state)
+	}
+	if err := os.Chtimes(target, past.Add(-time.Hour), past.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if state := state(load()); state != TranslationStale {
+		t.Fatalf("源文件比译文新时应为 stale，实际为 %s", state)
+	}
+}
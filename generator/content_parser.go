@@ -3,9 +3,11 @@ package generator
 import (
 	"fmt"
 	"hugo-content-suite/config"
+	"hugo-content-suite/models"
 	"hugo-content-suite/translator"
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 // ParagraphMapping 段落映射信息，用于追踪拆分的段落关系
@@ -82,13 +84,15 @@ func (c *ContentParser) applySplittingWithMapping(paragraphs []string) *SplitRes
 	return result
 }
 
-// MergeTranslatedParagraphs 合并翻译后的拆分段落
-func (c *ContentParser) MergeTranslatedParagraphs(translatedParagraphs []string, mappings []ParagraphMapping) ([]string, error) {
+// MergeTranslatedParagraphs 合并翻译后的拆分段落，片段之间的连接符由目标语言决定
+func (c *ContentParser) MergeTranslatedParagraphs(translatedParagraphs []string, mappings []ParagraphMapping, targetLang string) ([]string, error) {
 	if !c.config.Paragraph.MergeAfterTranslation {
 		// 如果配置为不合并，直接返回翻译后的段落
 		return translatedParagraphs, nil
 	}
 
+	spec, _ := c.config.Language.Spec(targetLang)
+	joiner := spec.Joiner()
 	var mergedParagraphs []string
 	var currentIndex int
 
@@ -110,8 +114,8 @@ func (c *ContentParser) MergeTranslatedParagraphs(translatedParagraphs []string,
 			}
 
 			if len(parts) > 0 {
-				// 合并拆分的段落为单个段落
-				merged := strings.Join(parts, " ")
+				// 合并拆分的段落为单个段落；中文、日文等不以空格分词的语言直接拼接
+				merged := strings.Join(parts, joiner)
 				mergedParagraphs = append(mergedParagraphs, merged)
 			}
 		}
@@ -325,3 +329,122 @@ func (c *ContentParser) LogParagraphSplitInfo(stats map[string]interface{}) {
 		fmt.Printf("📝 段落分析完成: %d个段落，无需拆分\n", originalCount)
 	}
 }
+
+// BlockUnits 返回块中需要交给模型的文本：整块翻译的块为全文，表格为含文字的单元格，
+// 原样保留的块没有。
+func (c *ContentParser) BlockUnits(block models.Block) []string {
+	switch block.Kind {
+	case models.BlockVerbatim:
+		return nil
+	case models.BlockPartial:
+		var units []string
+		for _, line := range strings.Split(block.Text, "\n") {
+			if tableDelimiterRegex.MatchString(line) {
+				continue
+			}
+			for _, cell := range splitTableRow(line) {
+				if cellNeedsTranslation(cell) {
+					units = append(units, cell)
+				}
+			}
+		}
+		return units
+	default:
+		return []string{block.Text}
+	}
+}
+
+// RebuildBlock 按 BlockUnits 的顺序用译文替换块中的文本。
+func (c *ContentParser) RebuildBlock(block models.Block, translated []string) string {
+	switch block.Kind {
+	case models.BlockVerbatim:
+		return block.Text
+	case models.BlockPartial:
+		lines := strings.Split(block.Text, "\n")
+		next := 0
+		for i, line := range lines {
+			if tableDelimiterRegex.MatchString(line) {
+				continue
+			}
+			cells := splitTableRow(line)
+			changed := false
+			for j, cell := range cells {
+				if cellNeedsTranslation(cell) && next < len(translated) {
+					// 单元格内不能换行，管道符需要转义
+					value := strings.Join(strings.Fields(translated[next]), " ")
+					cells[j] = strings.ReplaceAll(strings.ReplaceAll(value, `\|`, "|"), "|", `\|`)
+					next++
+					changed = true
+				}
+			}
+			if changed {
+				lines[i] = joinTableRow(line, cells)
+			}
+		}
+		return strings.Join(lines, "\n")
+	default:
+		if len(translated) == 0 {
+			return block.Text
+		}
+		return translated[0]
+	}
+}
+
+var (
+	tableDelimiterRegex = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	cellCodeSpanRegex   = regexp.MustCompile("`[^`]*`")
+)
+
+// splitTableRow 按未转义且不在行内代码中的管道符拆分表格行，返回去掉首尾空白的单元格。
+func splitTableRow(line string) []string {
+	row := strings.TrimSpace(line)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+
+	var cells []string
+	var current strings.Builder
+	inCode := false
+	for i := 0; i < len(row); i++ {
+		ch := row[i]
+		switch {
+		case ch == '\\' && i+1 < len(row):
+			current.WriteByte(ch)
+			current.WriteByte(row[i+1])
+			i++
+			continue
+		case ch == '`':
+			inCode = !inCode
+		case ch == '|' && !inCode:
+			cells = append(cells, strings.TrimSpace(current.String()))
+			current.Reset()
+			continue
+		}
+		current.WriteByte(ch)
+	}
+	return append(cells, strings.TrimSpace(current.String()))
+}
+
+// joinTableRow 按原行是否带首尾管道符重新拼接单元格。
+func joinTableRow(original string, cells []string) string {
+	trimmed := strings.TrimSpace(original)
+	row := strings.Join(cells, " | ")
+	if strings.HasPrefix(trimmed, "|") {
+		row = "| " + row
+	}
+	if strings.HasSuffix(trimmed, "|") {
+		row += " |"
+	}
+	return row
+}
+
+// cellNeedsTranslation 判断单元格去掉行内代码后是否还有文字。
+func cellNeedsTranslation(cell string) bool {
+	for _, r := range cellCodeSpanRegex.ReplaceAllString(cell, "") {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package generator
+
+import (
+	"hugo-content-suite/models"
+	"strings"
+	"testing"
+)
+
+func TestTableBlockTranslatesCellsOnly(t *testing.T) {
+	parser := &ContentParser{}
+	block := models.Block{Kind: models.BlockPartial, Type: "table", Text: "| 名称 | 代码 |\n|:---|---:|\n| 苹果 | `x|y` |\n| 42 | 香蕉 |"}
+
+	units := parser.BlockUnits(block)
+	if strings.Join(units, ",") != "名称,代码,苹果,香蕉" {
+		t.Fatalf("只应翻译含文字的单元格: %q", units)
+	}
+	got := parser.RebuildBlock(block, []string{"Name", "Code", "Apple", "Banana\nsplit|bar"})
+	want := "| Name | Code |\n|:---|---:|\n| Apple | `x|y` |\n| 42 | Banana split\\|bar |"
+	if got != want {
+		t.Fatalf("表格重建结果为\n%s\n应为\n%s", got, want)
+	}
+
+	verbatim := models.Block{Kind: models.BlockVerbatim, Type: "code", Text: "```\n代码\n```"}
+	if parser.BlockUnits(verbatim) != nil || parser.RebuildBlock(verbatim, nil) != verbatim.Text {
+		t.Fatal("原样块不应送去翻译")
+	}
+}
@@ -3,12 +3,13 @@ package generator
 import (
 	"fmt"
 	"hugo-content-suite/config"
+	"hugo-content-suite/frontmatter"
+	"hugo-content-suite/models"
 	"hugo-content-suite/translator"
 	"hugo-content-suite/utils"
+	"sort"
 	"strings"
 	"time"
-
-	"gopkg.in/yaml.v3"
 )
 
 // FieldTranslator 字段翻译器
@@ -25,106 +26,103 @@ func NewFieldTranslator() *FieldTranslator {
 	}
 }
 
-// translateFrontMatterToLanguage 翻译前置数据到指定语言
-func (a *ArticleTranslator) translateFrontMatterToLanguage(frontMatter, targetLang string) (string, error) {
+// translateFrontMatterToLanguage 翻译前置数据到指定语言。YAML 只原位替换可翻译的标量，
+// 其余键、注释与格式保持不变；sourceHash 记录在 translationSourceHash 中。existing 为已有译文时
+// 保留其中 keep-local 策略的字段。返回带分隔符的前置数据
+func (a *ArticleTranslator) translateFrontMatterToLanguage(frontMatter, format, targetLang, sourceHash string, existing *frontmatter.Document) (string, error) {
 	if strings.TrimSpace(frontMatter) == "" {
 		return frontMatter, nil
 	}
 
 	cfg := config.GetGlobalConfig()
-	targetLangName := cfg.Language.LanguageNames[targetLang]
-	if targetLangName == "" {
-		targetLangName = targetLang
-	}
+	targetLangName := cfg.Language.DisplayName(targetLang)
 
 	fmt.Printf("翻译前置数据到 %s...\n", targetLangName)
 
-	// 解析 YAML
-	var frontMatterData map[string]interface{}
-	if err := yaml.Unmarshal([]byte(frontMatter), &frontMatterData); err != nil {
-		return "", fmt.Errorf("解析前置数据失败: %v", err)
-	}
-
-	// 翻译各个字段
-	translatedData, err := a.translateFrontMatterFields(frontMatterData, targetLang)
-	if err != nil {
+	doc := &frontmatter.Document{Format: frontmatter.Format(format), FrontMatter: frontMatter}
+	if err := doc.EditStrings(func(path, value string) (string, bool) {
+		return a.translateFrontMatterValue(path, value, targetLang)
+	}); err != nil {
 		return "", fmt.Errorf("翻译前置数据字段失败: %v", err)
 	}
-
-	// 将翻译后的数据转换回 YAML
-	translatedYAML, err := yaml.Marshal(translatedData)
-	if err != nil {
-		return "", fmt.Errorf("生成翻译后的YAML失败: %v", err)
+	if err := keepLocalFields(doc, existing); err != nil {
+		return "", fmt.Errorf("保留译文字段失败: %v", err)
+	}
+	if sourceHash != "" {
+		if err := doc.SetString(models.TranslationSourceHashKey, sourceHash); err != nil {
+			return "", fmt.Errorf("记录源文件哈希失败: %v", err)
+		}
 	}
 
-	return "---\r\n" + string(translatedYAML) + "---\r\n", nil
+	return doc.Header(), nil
 }
 
-// translateFrontMatterFields 翻译前置数据的所有字段
-func (a *ArticleTranslator) translateFrontMatterFields(data map[string]interface{}, targetLang string) (map[string]interface{}, error) {
-	result := make(map[string]interface{})
-
-	// 定义需要翻译的字段
-	translatableFields := map[string]bool{
-		"title":       true,
-		"description": true,
+// keepLocalFields 把已有译文中 keep-local 策略的字段复制到新生成的前置数据
+func keepLocalFields(doc, existing *frontmatter.Document) error {
+	if existing == nil || existing.Format == frontmatter.FormatNone {
+		return nil
 	}
-
-	// 定义需要翻译的数组字段
-	translatableArrayFields := map[string]bool{
-		"tags":       true,
-		"categories": true,
+	local, err := existing.DecodeMap()
+	if err != nil {
+		return nil // 已有译文无法解析时按新生成处理
 	}
+	generated, err := doc.DecodeMap()
+	if err != nil {
+		return err
+	}
+	keys := keepLocalKeys(local, generated)
+	for _, key := range keys {
+		if err := doc.CopyField(existing, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	for key, value := range data {
-		switch {
-		case translatableFields[key]:
-			// 翻译单个字符串字段
-			if strValue, ok := value.(string); ok {
-				translatedValue, err := a.translateStringField(key, strValue, targetLang)
-				if err != nil {
-					fmt.Printf("  警告: 翻译字段 %s 失败: %v\n", key, err)
-					result[key] = value // 保持原值
-				} else {
-					result[key] = translatedValue
-				}
-			} else {
-				result[key] = value
-			}
-
-		case translatableArrayFields[key]:
-			// 翻译数组字段
-			if arrayValue, ok := value.([]interface{}); ok {
-				translatedArray, err := a.translateArrayField(key, arrayValue, targetLang)
-				if err != nil {
-					fmt.Printf("  警告: 翻译数组字段 %s 失败: %v\n", key, err)
-					result[key] = value // 保持原值
-				} else {
-					result[key] = translatedArray
-				}
-			} else {
-				result[key] = value
+// keepLocalKeys 返回各映射中 keep-local 策略的键（不含翻译流程维护的哈希），已排序
+func keepLocalKeys(maps ...map[string]interface{}) []string {
+	policies := config.GetGlobalConfig().FrontMatter
+	seen := make(map[string]bool)
+	var keys []string
+	for _, data := range maps {
+		for key := range data {
+			if !seen[key] && policies.Policy(key) == config.FieldKeepLocal && key != models.TranslationSourceHashKey {
+				seen[key] = true
+				keys = append(keys, key)
 			}
-
-		default:
-			// 其他字段保持不变
-			result[key] = value
 		}
 	}
+	sort.Strings(keys)
+	return keys
+}
+
+// translateFrontMatterValue 翻译单个前置数据字符串；数组字段按元素逐个调用。
+// 只翻译 front_matter.translatable_fields 中列出且所属顶层键为 translate 策略的字段
+func (a *ArticleTranslator) translateFrontMatterValue(path, value, targetLang string) (string, bool) {
+	frontMatterConfig := config.GetGlobalConfig().FrontMatter
+	field, ok := frontMatterConfig.Field(path)
+	root, _, _ := strings.Cut(path, ".")
+	if !ok || frontMatterConfig.Policy(root) != config.FieldTranslate {
+		return value, false
+	}
 
-	return result, nil
+	translatedValue, err := a.translateField(field, path, value, targetLang)
+	if err != nil {
+		fmt.Printf("  警告: 翻译字段 %s 失败: %v\n", path, err)
+		return value, false // 保持原值
+	}
+	return translatedValue, true
 }
 
-// translateStringField 翻译字符串字段
-func (a *ArticleTranslator) translateStringField(fieldName, value, targetLang string) (string, error) {
+// translateField 按字段配置的缓存与提示词模板翻译一个字符串
+func (a *ArticleTranslator) translateField(field config.TranslatableField, path, value, targetLang string) (string, error) {
 	if value == "" || !utils.ContainsChinese(value) {
 		return value, nil
 	}
 
-	fmt.Printf("  %s: %s -> ", fieldName, value)
+	fmt.Printf("  %s: %s -> ", path, value)
 
-	// 使用缓存翻译
-	translated, err := a.translationUtils.TranslateToLanguage(value, targetLang)
+	translated, err := a.translationUtils.TranslateField(value, targetLang, field.Cache, field.PromptTemplate)
 	if err != nil {
 		fmt.Printf("翻译失败\n")
 		return value, err
@@ -134,48 +132,6 @@ func (a *ArticleTranslator) translateStringField(fieldName, value, targetLang st
 	return translated, nil
 }
 
-// translateArrayField 翻译数组字段
-func (a *ArticleTranslator) translateArrayField(fieldName string, items []interface{}, targetLang string) ([]interface{}, error) {
-	if len(items) == 0 {
-		return items, nil
-	}
-
-	fmt.Printf("  %s: ", fieldName)
-
-	var translatedItems []interface{}
-	for _, item := range items {
-		if strItem, ok := item.(string); ok {
-			if utils.ContainsChinese(strItem) {
-				fmt.Printf("%s -> ", strItem)
-
-				var translated string
-				var err error
-				if fieldName == "tags" {
-					translated, err = a.translationUtils.TranslateTag(strItem, targetLang)
-				} else {
-					translated, err = a.translationUtils.TranslateCategory(strItem, targetLang)
-				}
-				if err != nil {
-					fmt.Printf("失败 ")
-					translatedItems = append(translatedItems, item)
-					continue
-				}
-
-				fmt.Printf("%s ", translated)
-				translatedItems = append(translatedItems, translated)
-			} else {
-				fmt.Printf("%s -> %s\t", strItem, strItem)
-				translatedItems = append(translatedItems, item)
-			}
-		} else {
-			translatedItems = append(translatedItems, item)
-		}
-	}
-
-	fmt.Printf("\n")
-	return translatedItems, nil
-}
-
 // printParagraphStageReport 打印段落翻译阶段报告
 func (a *ArticleTranslator) printParagraphStageReport(current, total int, elapsed time.Duration, success, error int) {
 	stage := (current + 9) / 10
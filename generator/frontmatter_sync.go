@@ -0,0 +1,225 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"hugo-content-suite/config"
+	"hugo-content-suite/frontmatter"
+	"hugo-content-suite/manifest"
+	"hugo-content-suite/models"
+	"hugo-content-suite/scanner"
+	"hugo-content-suite/utils"
+	"sort"
+	"strings"
+)
+
+// SyncFrontMatter 把源文件前置数据的变化同步到已有译文，不翻译正文：copy 字段直接使用源文件的值，
+// translate 字段只在源文本变化时重新翻译，keep-local 字段保持译文中的值
+func (a *ArticleTranslator) SyncFrontMatter(previews []ArticleTranslationPreview) error {
+	fmt.Println("\n🔁 同步前置数据")
+	fmt.Println("===============================")
+
+	synced, unchanged, failed := 0, 0, 0
+	for _, preview := range previews {
+		if preview.Status == TranslationMissing {
+			continue
+		}
+		changes, untracked, err := a.syncFrontMatter(preview.Article, preview.TargetFile, preview.TargetLang)
+		switch {
+		case err != nil:
+			fmt.Printf("  ❌ %s: %v\n", preview.TargetFile, err)
+			failed++
+			continue
+		case len(changes) == 0:
+			unchanged++
+		default:
+			fmt.Printf("  ✅ %s: %s\n", preview.TargetFile, strings.Join(changes, ", "))
+			synced++
+		}
+		if len(untracked) > 0 {
+			fmt.Printf("  ℹ️  %s: %s 缺少翻译记录，未同步；重新翻译该译文后即可跟踪\n",
+				preview.TargetFile, strings.Join(untracked, ", "))
+		}
+	}
+
+	fmt.Printf("\n🎉 同步完成: 更新 %d 个，无需更新 %d 个，失败 %d 个\n", synced, unchanged, failed)
+	utils.LogOperation("同步前置数据", map[string]interface{}{
+		"synced":    synced,
+		"unchanged": unchanged,
+		"failed":    failed,
+	})
+	return nil
+}
+
+// syncFrontMatter 同步一个译文的前置数据，返回改动的字段与因缺少翻译记录而跳过的字段。
+// 正文与快照中的源文一致且字段都已同步时，更新 translationSourceHash，译文不再显示为过期
+func (a *ArticleTranslator) syncFrontMatter(article models.Article, targetFile, targetLang string) ([]string, []string, error) {
+	if !scanner.HasContent(&article) {
+		if err := scanner.LoadContent(&article); err != nil {
+			return nil, nil, err
+		}
+	}
+	source := &frontmatter.Document{Format: frontmatter.Format(article.FrontMatterFormat), FrontMatter: article.FrontMatter}
+	target, err := frontmatter.ReadFile(targetFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target.Format == frontmatter.FormatNone {
+		return nil, nil, fmt.Errorf("译文没有前置数据")
+	}
+
+	// 译文与源文件一致时，源文件当前的值就是它的翻译依据
+	snapshot, hasSnapshot := manifest.LoadSnapshot(targetFile)
+	stampedHash, _, _ := target.GetString(models.TranslationSourceHashKey)
+	fields := make(map[string]string)
+	for key, value := range snapshot.Fields {
+		fields[key] = value
+	}
+	if snapshot.Fields == nil && stampedHash == article.SourceHash {
+		fields = translatedFieldValues(article)
+	}
+
+	changes, untracked, err := a.syncFields(target, source, fields, targetLang)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bodyCurrent := hasSnapshot && !snapshot.FieldsOnly && equalBlocks(snapshot.Source, article.BodyContent)
+	if bodyCurrent && len(untracked) == 0 && stampedHash != article.SourceHash {
+		if err := target.SetString(models.TranslationSourceHashKey, article.SourceHash); err != nil {
+			return nil, nil, fmt.Errorf("记录源文件哈希失败: %v", err)
+		}
+		changes = append(changes, models.TranslationSourceHashKey)
+	}
+
+	if len(changes) > 0 {
+		if err := manifest.UpdateFile(targetFile, manifest.KindTranslation, target.String()); err != nil {
+			return nil, nil, fmt.Errorf("写入目标文件失败: %w", err)
+		}
+	}
+	if len(changes) > 0 || (snapshot.Fields == nil && len(fields) > 0) {
+		if !hasSnapshot {
+			snapshot = manifest.Snapshot{FieldsOnly: true}
+		}
+		snapshot.Fields = fields
+		if err := manifest.SaveSnapshot(targetFile, snapshot); err != nil {
+			fmt.Printf("  ⚠️ 保存译文快照失败: %v\n", err)
+		}
+	}
+	return changes, untracked, nil
+}
+
+// syncFields 把源文件前置数据的变化写入译文 target：copy 字段直接使用源文件的值，translate 字段
+// 在源文本与 fields 中的记录不同时重新翻译并更新记录，返回改动的字段与因缺少记录而跳过的字段
+func (a *ArticleTranslator) syncFields(target, source *frontmatter.Document, fields map[string]string, targetLang string) ([]string, []string, error) {
+	sourceData, err := source.DecodeMap()
+	if err != nil {
+		return nil, nil, fmt.Errorf("源文件前置数据无效: %v", err)
+	}
+	targetData, err := target.DecodeMap()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policies := config.GetGlobalConfig().FrontMatter
+	var changes, untracked []string
+	for _, key := range unionKeys(sourceData, targetData) {
+		sourceValue, inSource := sourceData[key]
+		targetValue, inTarget := targetData[key]
+
+		switch policies.Policy(key) {
+		case config.FieldCopy:
+			if inSource == inTarget && encodeFieldValue(sourceValue) == encodeFieldValue(targetValue) {
+				continue
+			}
+			if err := target.CopyField(source, key); err != nil {
+				return nil, nil, fmt.Errorf("同步字段 %s 失败: %v", key, err)
+			}
+			changes = append(changes, key)
+
+		case config.FieldTranslate:
+			if !inSource {
+				delete(fields, key)
+				if inTarget {
+					if err := target.CopyField(source, key); err != nil {
+						return nil, nil, fmt.Errorf("删除字段 %s 失败: %v", key, err)
+					}
+					changes = append(changes, key+"（已删除）")
+				}
+				continue
+			}
+			encoded := encodeFieldValue(sourceValue)
+			previous, tracked := fields[key]
+			if tracked && previous == encoded && inTarget {
+				continue
+			}
+			if !tracked && inTarget {
+				untracked = append(untracked, key)
+				continue
+			}
+			if err := a.retranslateField(target, source, key, targetLang); err != nil {
+				return nil, nil, err
+			}
+			fields[key] = encoded
+			changes = append(changes, key+"（重新翻译）")
+		}
+	}
+	return changes, untracked, nil
+}
+
+// retranslateField 用源文件的字段替换译文中的同名字段，再只翻译该字段内的字符串
+func (a *ArticleTranslator) retranslateField(target, source *frontmatter.Document, key, targetLang string) error {
+	if err := target.CopyField(source, key); err != nil {
+		return fmt.Errorf("同步字段 %s 失败: %v", key, err)
+	}
+	if err := target.EditStrings(func(path, value string) (string, bool) {
+		if path != key && !strings.HasPrefix(path, key+".") {
+			return value, false
+		}
+		return a.translateFrontMatterValue(path, value, targetLang)
+	}); err != nil {
+		return fmt.Errorf("翻译字段 %s 失败: %v", key, err)
+	}
+	return nil
+}
+
+// translatedFieldValues 返回源文件中 translate 策略字段的编码值，作为译文快照的字段记录
+func translatedFieldValues(article models.Article) map[string]string {
+	source := &frontmatter.Document{Format: frontmatter.Format(article.FrontMatterFormat), FrontMatter: article.FrontMatter}
+	data, err := source.DecodeMap()
+	if err != nil {
+		return nil
+	}
+	policies := config.GetGlobalConfig().FrontMatter
+	fields := make(map[string]string)
+	for key, value := range data {
+		if policies.Policy(key) == config.FieldTranslate {
+			fields[key] = encodeFieldValue(value)
+		}
+	}
+	return fields
+}
+
+// encodeFieldValue 把解码后的字段值编码为可比较的文本，日期等类型在三种格式间一致
+func encodeFieldValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func unionKeys(maps ...map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, data := range maps {
+		for key := range data {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"hugo-content-suite/manifest"
+	"hugo-content-suite/models"
+	"hugo-content-suite/scanner"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyncFrontMatterCopiesFieldsWithoutRetranslating(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "index.md")
+	target := filepath.Join(dir, "index.en.md")
+	if err := os.WriteFile(source, []byte("---\ntitle: 标题\ndate: 2024-02-01\ndraft: false\ncover: new.png\n---\n正文\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	article := models.Article{FilePath: source}
+	if err := scanner.LoadContent(&article); err != nil {
+		t.Fatal(err)
+	}
+	// 译文生成时标题相同，正文与快照一致；之后源文件只改了日期、草稿状态和封面
+	if err := os.WriteFile(target, []byte("---\ntitle: Title\ndate: 2024-01-01\ndraft: true\ncover: old.png\naliases: [/en/old/]\ntranslationSourceHash: \"old\"\n---\nBody\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := manifest.SaveSnapshot(target, manifest.Snapshot{
+		Source: article.BodyContent, Translated: []string{"Body"}, Fields: translatedFieldValues(article),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, untracked, err := (&ArticleTranslator{}).syncFrontMatter(article, target, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(untracked) != 0 || strings.Join(changes, ",") != "cover,date,draft,"+models.TranslationSourceHashKey {
+		t.Fatalf("changes=%v untracked=%v", changes, untracked)
+	}
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "---\ntitle: Title\ndate: 2024-02-01\ndraft: false\ncover: new.png\naliases: [/en/old/]\ntranslationSourceHash: \"" + article.SourceHash + "\"\n---\nBody\n"
+	if string(data) != want {
+		t.Fatalf("同步结果:\n%s", data)
+	}
+}
+
+func TestMergeStampsSourceHashOnlyWhenFieldsAreTracked(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "index.md")
+	target := filepath.Join(dir, "index.en.md")
+	if err := os.WriteFile(source, []byte("---\ntitle: 新标题\n---\n正文\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	article := models.Article{FilePath: source}
+	if err := scanner.LoadContent(&article); err != nil {
+		t.Fatal(err)
+	}
+	// 正文未变、译文被人工润色；旧快照没有字段记录，无法判断标题是否需要重新翻译
+	if err := os.WriteFile(target, []byte("---\ntitle: Title\ntranslationSourceHash: \"old\"\n---\nPolished body\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := manifest.SaveSnapshot(target, manifest.Snapshot{Source: article.BodyContent, Translated: []string{"Body"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	translator := &ArticleTranslator{}
+	merge := func() string {
+		translated := 0
+		if err := translator.mergeTranslation(article, target, "en", 0, &translated, time.Now(), 0, 0); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	if got := merge(); got != "---\ntitle: Title\ntranslationSourceHash: \"old\"\n---\nPolished body\n" {
+		t.Fatalf("字段缺少翻译记录时不应更新源文件哈希:\n%s", got)
+	}
+
+	// 有字段记录且与源文件一致时，合并后的译文已是最新
+	if err := manifest.SaveSnapshot(target, manifest.Snapshot{
+		Source: article.BodyContent, Translated: []string{"Body"}, Fields: translatedFieldValues(article),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got := merge(); got != "---\ntitle: Title\ntranslationSourceHash: \""+article.SourceHash+"\"\n---\nPolished body\n" {
+		t.Fatalf("合并结果:\n%s", got)
+	}
+}
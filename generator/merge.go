@@ -0,0 +1,122 @@
+package generator
+
+import "strings"
+
+// 冲突标记沿用 Git 的格式，编辑器和 grep 都能直接定位
+const (
+	conflictOursMarker   = "<<<<<<< 手动修改"
+	conflictSplitMarker  = "======="
+	conflictTheirsMarker = ">>>>>>> 重新翻译"
+)
+
+// ConflictMarker 是合并冲突起始标记，供一致性检查查找未解决的冲突
+const ConflictMarker = conflictOursMarker
+
+// mergeBlocks 以 base 为共同祖先对 ours（人工修改后的译文块）与 theirs（按新源文生成的译文块）
+// 做三方合并。只有一方改动的区段直接采用改动方，两方改成相同内容时取其一，
+// 两方改动不同时输出带标记的冲突块。返回合并后的块与冲突数。
+func mergeBlocks(base, ours, theirs []string) ([]string, int) {
+	toOurs := matchIndex(base, ours)
+	toTheirs := matchIndex(base, theirs)
+
+	var merged []string
+	conflicts := 0
+	i, j, k := 0, 0, 0
+	for {
+		// 找到下一个在三方中都未改动的块作为稳定点
+		b := i
+		for ; b < len(base); b++ {
+			o, inOurs := toOurs[b]
+			t, inTheirs := toTheirs[b]
+			if inOurs && inTheirs && o >= j && t >= k {
+				break
+			}
+		}
+		oEnd, tEnd := len(ours), len(theirs)
+		if b < len(base) {
+			oEnd, tEnd = toOurs[b], toTheirs[b]
+		}
+
+		baseChunk, oursChunk, theirsChunk := base[i:b], ours[j:oEnd], theirs[k:tEnd]
+		if len(baseChunk) == len(oursChunk) && len(baseChunk) == len(theirsChunk) {
+			// 块数未变时逐块合并，相邻段落分别被人工和源文修改不算冲突
+			for n := range baseChunk {
+				block, conflict := mergeChunk(baseChunk[n:n+1], oursChunk[n:n+1], theirsChunk[n:n+1])
+				merged = append(merged, block...)
+				if conflict {
+					conflicts++
+				}
+			}
+		} else {
+			block, conflict := mergeChunk(baseChunk, oursChunk, theirsChunk)
+			merged = append(merged, block...)
+			if conflict {
+				conflicts++
+			}
+		}
+
+		if b == len(base) {
+			break
+		}
+		merged = append(merged, ours[oEnd])
+		i, j, k = b+1, oEnd+1, tEnd+1
+	}
+	return merged, conflicts
+}
+
+// mergeChunk 合并一个不稳定区段，两方改动不同时返回冲突块
+func mergeChunk(base, ours, theirs []string) ([]string, bool) {
+	switch {
+	case equalBlocks(base, ours):
+		return theirs, false
+	case equalBlocks(base, theirs), equalBlocks(ours, theirs):
+		return ours, false
+	default:
+		return []string{conflictOursMarker + "\n" + strings.Join(ours, "\n\n") + "\n" +
+			conflictSplitMarker + "\n" + strings.Join(theirs, "\n\n") + "\n" + conflictTheirsMarker}, true
+	}
+}
+
+// matchIndex 用最长公共子序列对齐两组块，返回 a 中下标到 b 中下标的映射
+func matchIndex(a, b []string) map[int]int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	matches := make(map[int]int)
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			matches[i] = j
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return matches
+}
+
+func equalBlocks(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeBlocksKeepsEditsInUnchangedParagraphs(t *testing.T) {
+	base := []string{"# Title", "First.", "Second.", "Third."}
+	ours := []string{"# Title", "First, polished.", "Second.", "Third.", "Editor note."}
+	theirs := []string{"# Title", "First.", "Second, revised.", "Third."}
+
+	merged, conflicts := mergeBlocks(base, ours, theirs)
+	want := []string{"# Title", "First, polished.", "Second, revised.", "Third.", "Editor note."}
+	if conflicts != 0 || strings.Join(merged, "|") != strings.Join(want, "|") {
+		t.Fatalf("合并结果为 %q（冲突 %d），应为 %q", merged, conflicts, want)
+	}
+}
+
+func TestMergeBlocksMarksConflicts(t *testing.T) {
+	base := []string{"A", "B", "C"}
+	ours := []string{"A", "B by editor", "C"}
+	theirs := []string{"A", "B retranslated", "C"}
+
+	merged, conflicts := mergeBlocks(base, ours, theirs)
+	if conflicts != 1 || len(merged) != 3 {
+		t.Fatalf("应产生 1 处冲突: %q", merged)
+	}
+	want := conflictOursMarker + "\nB by editor\n" + conflictSplitMarker + "\nB retranslated\n" + conflictTheirsMarker
+	if merged[1] != want {
+		t.Fatalf("冲突块为\n%s\n应为\n%s", merged[1], want)
+	}
+}
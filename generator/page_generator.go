@@ -1,9 +1,12 @@
 package generator
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"hugo-content-suite/config"
+	"hugo-content-suite/frontmatter"
+	"hugo-content-suite/layout"
+	"hugo-content-suite/manifest"
 	"hugo-content-suite/models"
 	"hugo-content-suite/scanner"
 	"hugo-content-suite/translator"
@@ -11,84 +14,146 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
 
-// TagPagePreview 标签页面预览信息
-type TagPagePreview struct {
-	TagName       string
-	Slug          string
+// TermPagePreview 分类法词条页面预览信息，每种语言的页面各一条
+type TermPagePreview struct {
+	Term          string
+	Lang          string // 目标语言，源语言页面为空
+	Title         string // 页面标题：源语言为词条，其他语言为词条译名
+	Slug          string // 各语言共用同一个 slug，多语言分类 URL 一一对应
 	ArticleCount  int
 	DirectoryPath string
 	FilePath      string
-	Status        string // "create", "update"
+	IndexFile     string            // 页面文件的完整路径
+	Titles        map[string]string // 同一词条各语言页面的标题，键为语言代码，供模板使用
+	Status        string            // "create", "update", "skip", "conflict"
+	Conflict      string            // 目录冲突的说明，Status 为 "conflict" 时有值
+	Collision     string            // 词条译名生成的 slug 与同一分类法中其他词条重复时的说明
 	ExistingSlug  string
 }
 
 // 实现 StatusLike 接口
-func (t TagPagePreview) GetStatus() string {
+func (t TermPagePreview) GetStatus() string {
 	return t.Status
 }
 
-// TagPageGenerator 标签页面生成器
-type TagPageGenerator struct {
+// TermPageGenerator 分类法词条页面生成器，标签、分类与自定义分类法共用同一流程
+type TermPageGenerator struct {
 	contentDir       string
+	taxonomy         config.Taxonomy
+	layout           layout.Layout
 	translationUtils *translator.TranslationUtils
-	slugCache        map[string]string
+	filter           models.ArticleFilter
+	conflictPolicy   manifest.Policy
+	slugEngine       string
+	skipped          int
 }
 
-// NewTagPageGenerator 创建新的标签页面生成器
-func NewTagPageGenerator(contentDir string) *TagPageGenerator {
-	return &TagPageGenerator{
+// NewTermPageGenerator 创建分类法 taxonomy 的词条页面生成器
+func NewTermPageGenerator(contentDir string, taxonomy config.Taxonomy) *TermPageGenerator {
+	return &TermPageGenerator{
 		contentDir:       contentDir,
+		taxonomy:         taxonomy,
+		layout:           layout.ForContentDir(contentDir),
 		translationUtils: translator.NewTranslationUtils(),
-		slugCache:        make(map[string]string),
+		slugEngine:       config.GetGlobalConfig().Slug.Engine,
 	}
 }
 
-// GenerateTagPagesWithMode 根据模式生成标签页面文件
-func (g *TagPageGenerator) GenerateTagPagesWithMode(targetPreviews []TagPagePreview, mode string) error {
-	fmt.Println("\n🏷️  标签页面生成器 (模式选择)")
+// Taxonomy 返回生成器处理的分类法
+func (g *TermPageGenerator) Taxonomy() config.Taxonomy {
+	return g.taxonomy
+}
+
+// SetFilter 设置筛选条件，之后的扫描只处理满足条件的文章
+func (g *TermPageGenerator) SetFilter(filter models.ArticleFilter) {
+	g.filter = filter
+}
+
+// SkippedFiles 返回上次扫描中 front matter 无法解析而跳过的文件数
+func (g *TermPageGenerator) SkippedFiles() int {
+	return g.skipped
+}
+
+// SetSlugEngine 设置本次使用的 slug 生成方式（llm 或 pinyin），默认取 slug.engine
+func (g *TermPageGenerator) SetSlugEngine(engine string) {
+	g.slugEngine = engine
+}
+
+// SetConflictPolicy 设置词条页面在上次生成后被手动修改时的处理方式，默认跳过
+func (g *TermPageGenerator) SetConflictPolicy(policy manifest.Policy) {
+	g.conflictPolicy = policy
+}
+
+// ModifiedPages 返回预览中上次生成后 title 或 slug 被手动修改过的词条页面
+func (g *TermPageGenerator) ModifiedPages(previews []TermPagePreview) []string {
+	var modified []string
+	for _, preview := range previews {
+		if preview.Status == "update" && termPageModified(preview.IndexFile, manifest.TermPageKind(g.taxonomy.Singular)) {
+			modified = append(modified, preview.IndexFile)
+		}
+	}
+	return modified
+}
+
+// GeneratePagesWithMode 根据模式生成词条页面文件
+func (g *TermPageGenerator) GeneratePagesWithMode(targetPreviews []TermPagePreview, mode string) error {
+	label := g.taxonomy.Label()
+	fmt.Printf("\n🏷️  %s页面生成器 (模式选择)\n", label)
 	fmt.Println("===============================")
 
 	if len(targetPreviews) == 0 {
-		fmt.Printf("ℹ️  根据选择的模式 '%s'，没有需要处理的标签\n", mode)
+		fmt.Printf("ℹ️  根据选择的模式 '%s'，没有需要处理的%s\n", mode, label)
 		return nil
 	}
 
-	fmt.Printf("📊 将处理 %d 个标签 (模式: %s)\n", len(targetPreviews), mode)
+	fmt.Printf("📊 将处理 %d 个%s页面 (模式: %s)\n", len(targetPreviews), label, mode)
 
-	tagsDir := config.GetGlobalConfig().Paths.TagsDir
-	if err := utils.EnsureDir(tagsDir); err != nil {
-		return fmt.Errorf("❌ 创建tags目录失败: %v", err)
+	if err := utils.EnsureDir(g.taxonomy.Dir); err != nil {
+		return fmt.Errorf("❌ 创建%s目录失败: %v", g.taxonomy.Plural, err)
 	}
 
-	return g.processTargetPreviews(targetPreviews, tagsDir)
+	return g.processTargetPreviews(targetPreviews)
 }
 
 // processTargetPreviews 处理目标预览
-func (g *TagPageGenerator) processTargetPreviews(targetPreviews []TagPagePreview, tagsDir string) error {
+func (g *TermPageGenerator) processTargetPreviews(targetPreviews []TermPagePreview) error {
 	createdCount := 0
 	updatedCount := 0
+	skippedCount := 0
 	errorCount := 0
 
-	fmt.Printf("\n📝 正在生成标签页面...\n")
+	fmt.Printf("\n📝 正在生成%s页面...\n", g.taxonomy.Label())
 	fmt.Println("========================")
 
 	for i, preview := range targetPreviews {
-		fmt.Printf("  [%d/%d] %s", i+1, len(targetPreviews), preview.TagName)
-
-		tagDir := filepath.Join(tagsDir, preview.TagName)
-		indexFile := filepath.Join(tagDir, "_index.md")
-		content := g.GenerateTagContent(preview.TagName, preview.Slug)
+		fmt.Printf("  [%d/%d] %s", i+1, len(targetPreviews), preview.Term)
+		if preview.Lang != "" {
+			fmt.Printf(" [%s] %s", preview.Lang, preview.Title)
+		}
 
-		if err := utils.WriteFileContent(indexFile, content); err != nil {
+		indexFile := preview.IndexFile
+		writtenFile, err := g.writePage(preview)
+		if errors.Is(err, manifest.ErrModified) {
+			fmt.Printf(" ⏭️  已手动修改，跳过\n")
+			skippedCount++
+			continue
+		}
+		if err != nil {
 			fmt.Printf(" ❌ 失败\n")
 			fmt.Printf("     错误: %v\n", err)
 			errorCount++
 			continue
 		}
+		if writtenFile != indexFile {
+			fmt.Printf(" 📄 已手动修改，另存为 %s\n", writtenFile)
+			skippedCount++
+			continue
+		}
 
 		if preview.Status == "create" {
 			fmt.Printf(" ✨ 新建\n")
@@ -101,9 +166,12 @@ func (g *TagPageGenerator) processTargetPreviews(targetPreviews []TagPagePreview
 		}
 	}
 
-	fmt.Printf("\n🎉 标签页面生成完成！\n")
+	fmt.Printf("\n🎉 %s页面生成完成！\n", g.taxonomy.Label())
 	fmt.Printf("   ✨ 新建: %d 个\n", createdCount)
 	fmt.Printf("   🔄 更新: %d 个\n", updatedCount)
+	if skippedCount > 0 {
+		fmt.Printf("   ⏭️  保留手动修改: %d 个\n", skippedCount)
+	}
 	if errorCount > 0 {
 		fmt.Printf("   ❌ 失败: %d 个\n", errorCount)
 	}
@@ -112,118 +180,197 @@ func (g *TagPageGenerator) processTargetPreviews(targetPreviews []TagPagePreview
 	return nil
 }
 
-func (g *TagPageGenerator) calculateTagStats(articles []models.Article) []models.TagStats {
-	tagMap := make(map[string]*models.TagStats)
+// calculateTermStats 统计各词条的文章数。Hugo 按 urlize 后的名称识别词条，Go 与 go 是同一词条，
+// 这里合并为一项，名称取使用最多的写法，次数相同时取排序靠前的写法
+func (g *TermPageGenerator) calculateTermStats(articles []models.Article) []models.TagStats {
+	type termGroup struct {
+		stat     models.TagStats
+		variants map[string]int
+		files    map[string]bool
+	}
+	groups := make(map[string]*termGroup)
 
 	for _, article := range articles {
-		for _, tag := range article.Tags {
-			if _, exists := tagMap[tag]; !exists {
-				tagMap[tag] = &models.TagStats{
-					Name:  tag,
-					Count: 0,
-					Files: []string{},
-				}
+		for _, term := range article.TaxonomyTerms(g.taxonomy.Plural) {
+			key := utils.TermDirName(term)
+			if key == "" {
+				key = term
+			}
+			group, exists := groups[key]
+			if !exists {
+				group = &termGroup{stat: models.TagStats{Files: []string{}}, variants: make(map[string]int), files: make(map[string]bool)}
+				groups[key] = group
+			}
+			group.variants[term]++
+			if !group.files[article.FilePath] {
+				group.files[article.FilePath] = true
+				group.stat.Count++
+				group.stat.Files = append(group.stat.Files, article.FilePath)
 			}
-			tagMap[tag].Count++
-			tagMap[tag].Files = append(tagMap[tag].Files, article.FilePath)
 		}
 	}
 
 	var stats []models.TagStats
-	for _, stat := range tagMap {
-		stats = append(stats, *stat)
+	for _, group := range groups {
+		best := 0
+		for name, count := range group.variants {
+			if count > best || (count == best && name < group.stat.Name) {
+				group.stat.Name, best = name, count
+			}
+		}
+		stats = append(stats, group.stat)
 	}
 
 	sort.Slice(stats, func(i, j int) bool {
-		return stats[i].Count > stats[j].Count
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Name < stats[j].Name
 	})
 
 	return stats
 }
 
-// PreviewTagPages 预览即将生成的标签页面
-func (g *TagPageGenerator) PrepareTagPages() ([]TagPagePreview, int, int) {
-	var previews []TagPagePreview
+// PreparePages 预览即将生成的词条页面
+func (g *TermPageGenerator) PreparePages() ([]TermPagePreview, int, int) {
+	var previews []TermPagePreview
+	label := g.taxonomy.Label()
 
 	// 扫描文章
-	articles, err := scanner.ScanArticles(g.contentDir)
+	articles, issues, err := scanner.ScanArticlesWithIssues(g.contentDir, false)
 	if err != nil {
 		return nil, 0, 0
 	}
+	g.skipped = scanner.Skipped(issues)
+	articles = g.filter.Apply(articles, g.contentDir)
 
-	tagStats := g.calculateTagStats(articles)
+	termStats := g.calculateTermStats(articles)
+	if len(termStats) == 0 {
+		fmt.Printf("ℹ️  文章中没有%s\n", label)
+		return nil, 0, 0
+	}
 
-	// 测试LM Studio连接
-	fmt.Print("🔗 测试LM Studio连接... ")
-	if err := g.translationUtils.TestConnection(); err != nil {
-		fmt.Printf("❌ 失败 (%v)\n", err)
-		fmt.Println("⚠️  无法连接AI翻译，终止操作")
+	// 测试LM Studio连接；连接失败时按配置改用拼音生成 slug，需要翻译的其他语言页面本次跳过
+	connected := connectModel(g.translationUtils)
+	engine, err := slugEngineFor(g.slugEngine, connected)
+	if err != nil {
 		return previews, 0, 0
-	} else {
-		fmt.Println("✅ 成功")
+	}
+	skipTranslated := !connected && g.taxonomy.Translated() && len(config.GetGlobalConfig().Language.TargetLanguages) > 0
+	if skipTranslated {
+		fmt.Printf("⚠️  模型不可用，本次只处理源语言页面与无需翻译的%s\n", label)
 	}
 
-	// 收集所有标签名
-	tagNames := make([]string, len(tagStats))
-	for i, stat := range tagStats {
-		tagNames[i] = stat.Name
+	// 收集所有词条
+	terms := make([]string, len(termStats))
+	for i, stat := range termStats {
+		terms[i] = stat.Name
 	}
 
-	fmt.Printf("🌐 正在生成 %d 个标签的slug...\n", len(tagNames))
+	fmt.Printf("🌐 正在生成 %d 个%s的slug...\n", len(terms), label)
 
-	// 使用AI批量翻译（带缓存）
-	slugMap, err := g.translationUtils.TranslateTags(tagNames)
+	// 使用AI批量翻译（带缓存，与文章前置数据中该分类法的英文翻译共用缓存）或拼音生成slug，
+	// 格式化后按 slug 规范去掉虚词、限制长度
+	slugMap, err := generateSlugs(terms, engine, func(texts []string) (map[string]string, error) {
+		return g.translationUtils.TranslateTerms(texts, g.taxonomy.Cache)
+	})
 	if err != nil {
 		fmt.Printf("⚠️ 批量翻译失败: %v\n", err)
 		return previews, 0, 0
 	}
 
-	// 格式化所有slug
-	for tag, slug := range slugMap {
-		slugMap[tag] = utils.FormatSlugField(slug)
-	}
-
-	fmt.Printf("\n📊 正在分析标签状态...\n")
+	fmt.Printf("\n📊 正在分析%s状态...\n", label)
+	cfg := config.GetGlobalConfig()
+	termsDir := g.taxonomy.Dir
 	createCount := 0
 	updateCount := 0
 
-	for i, stat := range tagStats {
-		fmt.Printf("  [%d/%d] 检查: %s", i+1, len(tagStats), stat.Name)
-
-		var status string
-
-		// 检查标签目录是否已存在
-		tagsDir := config.GetGlobalConfig().Paths.TagsDir
-		tagDir := filepath.Join(tagsDir, stat.Name)
-		indexFile := filepath.Join(tagDir, "_index.md")
-
-		existingSlug := g.ExtractSlugFromFile(indexFile)
-		if !utils.FileExists(indexFile) {
-			status = "create"
-			createCount++
-			fmt.Printf(" ✨ 需要新建\n")
-		} else if existingSlug != slugMap[stat.Name] {
-			status = "update"
-			updateCount++
-			fmt.Printf(" 🔄 需要更新\n")
-		} else {
-			status = "skip"
-			fmt.Printf(" ✅ 已是最新\n")
+	// 源语言页面在分类法目录下，其他语言的页面位于词条译名对应的目录，
+	// 目录名按 Hugo urlize 的规则生成，文件名或内容目录按当前布局区分语言
+	groups := make([][]TermPagePreview, len(termStats))
+	for i, stat := range termStats {
+		pages := []TermPagePreview{termPage(stat.Name, "", stat.Name, termsDir)}
+		for _, lang := range cfg.Language.TargetLanguages {
+			if skipTranslated && utils.ContainsChinese(stat.Name) {
+				break
+			}
+			term, err := g.termTranslation(stat.Name, lang)
+			if err != nil {
+				fmt.Printf("  ⚠️ %s [%s] 翻译%s失败，跳过: %v\n", stat.Name, lang, label, err)
+				continue
+			}
+			page := termPage(stat.Name, lang, term, termsDir)
+			if page.IndexFile = g.layout.TranslationPath(page.IndexFile, lang); page.IndexFile == "" {
+				fmt.Printf("  ⚠️ %s [%s] %s目录不在源语言内容目录中，跳过\n", stat.Name, lang, label)
+				continue
+			}
+			pages = append(pages, page)
 		}
+		groups[i] = pages
+	}
+	markDirConflicts(groups)
+	conflictCount := 0
+	resolved := g.resolveTermSlugs(groups, slugMap)
+	collisionCount := 0
+
+	for i, stat := range termStats {
+		fmt.Printf("  [%d/%d] 检查: %s\n", i+1, len(termStats), stat.Name)
+		slug := resolved[i].Slug
+		pages := groups[i]
+		if resolved[i].Collision != "" {
+			collisionCount++
+			fmt.Printf("     ⚠️ slug %s，使用 %s\n", resolved[i].Collision, slug)
+		}
+		titles := make(map[string]string, len(pages))
+		for _, page := range pages {
+			lang := page.Lang
+			if lang == "" {
+				lang = cfg.Language.SourceLanguage
+			}
+			titles[lang] = page.Title
+		}
+
+		for _, page := range pages {
+			pageLabel := "源语言"
+			if page.Lang != "" {
+				pageLabel = fmt.Sprintf("%s: %s", page.Lang, page.Title)
+			}
+			fmt.Printf("     %s", pageLabel)
+
+			var status string
+			existingSlug := g.ExtractSlugFromFile(page.IndexFile)
+			if page.Conflict != "" {
+				status = "conflict"
+				conflictCount++
+				fmt.Printf(" ⚠️  目录冲突: %s\n", page.Conflict)
+			} else if !utils.FileExists(page.IndexFile) {
+				status = "create"
+				createCount++
+				fmt.Printf(" ✨ 需要新建\n")
+			} else if existingSlug != slug || pageStringField(page.IndexFile, "title") != page.Title {
+				status = "update"
+				updateCount++
+				fmt.Printf(" 🔄 需要更新\n")
+			} else {
+				status = "skip"
+				fmt.Printf(" ✅ 已是最新\n")
+			}
 
-		// 生成slug（从映射中获取）
-		slug := slugMap[stat.Name]
-
-		preview := TagPagePreview{
-			TagName:       stat.Name,
-			Slug:          slug,
-			ArticleCount:  stat.Count,
-			DirectoryPath: fmt.Sprintf("tags/%s/", stat.Name),
-			FilePath:      fmt.Sprintf("tags/%s/_index.md", stat.Name),
-			Status:        status,
-			ExistingSlug:  existingSlug,
+			page.Slug = slug
+			page.Collision = resolved[i].Collision
+			page.ArticleCount = stat.Count
+			page.Titles = titles
+			page.Status = status
+			page.ExistingSlug = existingSlug
+			if rel, err := filepath.Rel(filepath.Dir(termsDir), page.IndexFile); err == nil {
+				page.FilePath = rel
+				page.DirectoryPath = filepath.Dir(rel) + string(filepath.Separator)
+			} else {
+				page.FilePath = page.IndexFile
+				page.DirectoryPath = filepath.Dir(page.IndexFile)
+			}
+			previews = append(previews, page)
 		}
-		previews = append(previews, preview)
 
 		time.Sleep(10 * time.Millisecond) // 短暂延迟
 	}
@@ -231,56 +378,247 @@ func (g *TagPageGenerator) PrepareTagPages() ([]TagPagePreview, int, int) {
 	fmt.Printf("\n📈 统计结果:\n")
 	fmt.Printf("   ✨ 需要新建: %d 个\n", createCount)
 	fmt.Printf("   🔄 需要更新: %d 个\n", updateCount)
+	if conflictCount > 0 {
+		fmt.Printf("   ⚠️  目录冲突，不会写入: %d 个\n", conflictCount)
+	}
+	if collisionCount > 0 {
+		fmt.Printf("   ⚠️  slug 重复已加后缀: %d 个%s\n", collisionCount, label)
+	}
 	fmt.Printf("   📦 总计: %d 个\n", len(previews))
 
 	return previews, createCount, updateCount
 }
 
-// GenerateTagContent 生成标签页面内容
-func (g *TagPageGenerator) GenerateTagContent(tagName, slug string) string {
-	return fmt.Sprintf(`---
-title: %s
-slug: "%s"
----
-`, tagName, slug)
-}
-
-// ExtractSlugFromFile 从标签页面文件中提取现有的slug
-func (g *TagPageGenerator) ExtractSlugFromFile(filePath string) string {
-	if !utils.FileExists(filePath) {
-		return ""
+// resolveTermSlugs 在分类法内为各词条去重 slug，返回与 groups 一一对应的结果。
+// 分类法目录中不属于本次词条的源语言页面（如被筛选排除的词条）的 slug 视为已占用；
+// 词条按文章数从多到少排列，使用较多的词条保留原 slug
+func (g *TermPageGenerator) resolveTermSlugs(groups [][]TermPagePreview, slugMap map[string]string) []slugResolution {
+	processing := make(map[string]bool, len(groups))
+	claims := make([]slugClaim, len(groups))
+	for i, pages := range groups {
+		source := pages[0]
+		processing[source.IndexFile] = true
+		claims[i] = slugClaim{Owner: source.Term, Proposed: slugMap[source.Term], Current: g.ExtractSlugFromFile(source.IndexFile)}
 	}
 
-	file, err := os.Open(filePath)
-	if err != nil {
-		return ""
+	taken := make(map[string]string)
+	existing, _ := filepath.Glob(filepath.Join(g.taxonomy.Dir, "*", "_index.md"))
+	for _, path := range existing {
+		if processing[path] {
+			continue
+		}
+		if slug := pageStringField(path, "slug"); slug != "" {
+			taken[slug] = filepath.Base(filepath.Dir(path))
+		}
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	inFrontMatter := false
+	return resolveSlugs(claims, taken)
+}
 
-	for scanner.Scan() {
-		line := scanner.Text()
+// termPage 返回词条页面在源语言中的位置，目录名为标题按 Hugo urlize 规则生成的名称。
+// 目录名无法直接使用（为空、含路径分隔符、以点开头等）时页面标记为冲突，不会改写到其他目录
+func termPage(term, lang, title, termsDir string) TermPagePreview {
+	page := TermPagePreview{Term: term, Lang: lang, Title: title}
+	name := utils.TermDirName(title)
+	if problem := utils.TermDirProblem(name); problem != "" {
+		page.Conflict = problem
+		if name == "" {
+			name = title
+		}
+	}
+	page.IndexFile = filepath.Join(termsDir, name, "_index.md")
+	return page
+}
 
-		if strings.TrimSpace(line) == "---" {
-			if !inFrontMatter {
-				inFrontMatter = true
+// markDirConflicts 在写入前检查词条页面目录，把冲突说明写入页面的 Conflict：
+// 不同词条的译名映射到同一页面（路径比较不区分大小写，macOS 与 Windows 上它们是同一文件）、
+// 磁盘上已有仅大小写不同的目录，或者词条已有按原名命名的旧目录。
+// 冲突的页面不会写入，需要手动处理
+func markDirConflicts(groups [][]TermPagePreview) {
+	owners := make(map[string]*TermPagePreview)
+	entries := make(map[string][]os.DirEntry)
+	for i := range groups {
+		for j := range groups[i] {
+			page := &groups[i][j]
+			if page.Conflict != "" {
 				continue
-			} else {
-				break
+			}
+			key := strings.ToLower(page.IndexFile)
+			if owner, ok := owners[key]; ok {
+				if owner.Term != page.Term {
+					page.Conflict = fmt.Sprintf("与词条 %q 使用同一目录", owner.Term)
+					if owner.Conflict == "" {
+						owner.Conflict = fmt.Sprintf("与词条 %q 使用同一目录", page.Term)
+					}
+				}
+				continue
+			}
+			owners[key] = page
+
+			dir := filepath.Dir(page.IndexFile)
+			name := filepath.Base(dir)
+			parent := filepath.Dir(dir)
+			if _, ok := entries[parent]; !ok {
+				entries[parent], _ = os.ReadDir(parent)
+			}
+			for _, entry := range entries[parent] {
+				if !entry.IsDir() || entry.Name() == name {
+					continue
+				}
+				if strings.EqualFold(entry.Name(), name) {
+					page.Conflict = fmt.Sprintf("已有仅大小写不同的目录 %s", entry.Name())
+					break
+				}
+				if entry.Name() == page.Title && utils.FileExists(filepath.Join(parent, entry.Name(), filepath.Base(page.IndexFile))) {
+					page.Conflict = fmt.Sprintf("已有按原词条命名的目录 %s，请改名为 %s", entry.Name(), name)
+					break
+				}
 			}
 		}
+	}
+}
+
+// termTranslation 返回词条在目标语言下的名称。与文章前置数据中该分类法字段的翻译使用同一缓存和提示词，
+// 译文文章中的词条与词条页面因此是同一个词；copy 策略的分类法在各语言中保持原文
+func (g *TermPageGenerator) termTranslation(term, lang string) (string, error) {
+	if !g.taxonomy.Translated() || !utils.ContainsChinese(term) {
+		return term, nil
+	}
+	field, ok := config.GetGlobalConfig().FrontMatter.Field(g.taxonomy.Plural)
+	if !ok {
+		field = config.TranslatableField{Cache: g.taxonomy.Cache, PromptTemplate: g.taxonomy.PromptTemplate}
+	}
+	return g.translationUtils.TranslateField(term, lang, field.Cache, field.PromptTemplate)
+}
 
-		if inFrontMatter && strings.HasPrefix(strings.TrimSpace(line), "slug:") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				slug := strings.TrimSpace(parts[1])
-				slug = strings.Trim(slug, "\"'")
-				return slug
+// writePage 写入词条页面并返回实际写入的路径。已存在的页面只改写受管理的 title、slug 字段，
+// 其他字段与正文保持原样；新页面由 GenerateContent 生成。生成清单记录受管理字段的值，
+// 只有这两个字段被手动改过时才按冲突策略处理
+func (g *TermPageGenerator) writePage(preview TermPagePreview) (string, error) {
+	kind := manifest.TermPageKind(g.taxonomy.Singular)
+	path := preview.IndexFile
+
+	var content string
+	if utils.FileExists(path) {
+		doc, err := frontmatter.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		if err := setManagedFields(doc, preview); err != nil {
+			return "", err
+		}
+		content = doc.String()
+		if termPageModified(path, kind) {
+			switch g.conflictPolicy {
+			case manifest.PolicyOverwrite:
+			case manifest.PolicySaveNew:
+				newPath := path + manifest.NewSuffix
+				return newPath, utils.WriteFileContent(newPath, content)
+			default:
+				return "", manifest.ErrModified
 			}
 		}
+	} else {
+		var err error
+		if content, err = g.GenerateContent(preview); err != nil {
+			return "", err
+		}
+	}
+
+	if err := utils.WriteFileContent(path, content); err != nil {
+		return "", err
+	}
+	if err := manifest.Record(path, kind, managedFieldValues(preview.Title, preview.Slug)); err != nil {
+		return path, fmt.Errorf("更新生成清单失败: %w", err)
+	}
+	return path, nil
+}
+
+// GenerateContent 生成新词条页面的内容。分类法配置了模板文件时使用模板，占位符 {name}、{title}、
+// {slug}、{count} 替换为词条名、页面标题、slug 与文章数，{title.<语言>} 替换为该语言页面的标题；
+// 模板的 front matter 中 title、slug 总是写为页面的值
+func (g *TermPageGenerator) GenerateContent(preview TermPagePreview) (string, error) {
+	if g.taxonomy.Template == "" {
+		return termPageContent(preview.Title, preview.Slug), nil
+	}
+	data, err := os.ReadFile(g.taxonomy.Template)
+	if err != nil {
+		return "", fmt.Errorf("读取%s页面模板失败: %v", g.taxonomy.Label(), err)
 	}
 
-	return ""
+	pairs := []string{
+		"{name}", preview.Term,
+		"{title}", preview.Title,
+		"{slug}", preview.Slug,
+		"{count}", strconv.Itoa(preview.ArticleCount),
+	}
+	for lang, title := range preview.Titles {
+		pairs = append(pairs, "{title."+lang+"}", title)
+	}
+	doc, err := frontmatter.Parse(strings.NewReplacer(pairs...).Replace(string(data)))
+	if err != nil {
+		return "", fmt.Errorf("%s页面模板无效: %v", g.taxonomy.Label(), err)
+	}
+	if err := setManagedFields(doc, preview); err != nil {
+		return "", fmt.Errorf("%s页面模板无效: %v", g.taxonomy.Label(), err)
+	}
+	return doc.String(), nil
+}
+
+// setManagedFields 写入工具管理的 title 与 slug
+func setManagedFields(doc *frontmatter.Document, preview TermPagePreview) error {
+	if err := doc.SetString("title", preview.Title); err != nil {
+		return err
+	}
+	return doc.SetString("slug", preview.Slug)
+}
+
+// managedFieldValues 是生成清单中词条页面的记录内容
+func managedFieldValues(title, slug string) string {
+	return title + "\x00" + slug
+}
+
+// termPageModified 判断词条页面的 title 或 slug 是否在上次生成后被手动修改。
+// 旧版本的记录是整个文件的哈希，文件未变时同样视为未修改
+func termPageModified(path, kind string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil || !manifest.Modified(path, kind, string(data)) {
+		return false
+	}
+	doc, err := frontmatter.Parse(string(data))
+	if err != nil {
+		return true
+	}
+	title, _, _ := doc.GetString("title")
+	slug, _, _ := doc.GetString("slug")
+	return manifest.Modified(path, kind, managedFieldValues(title, slug))
+}
+
+// ExtractSlugFromFile 从词条页面文件中提取现有的slug
+func (g *TermPageGenerator) ExtractSlugFromFile(filePath string) string {
+	return pageStringField(filePath, "slug")
+}
+
+// termPageContent 生成词条页面的内置内容
+func termPageContent(title, slug string) string {
+	return fmt.Sprintf(`---
+title: %q
+slug: "%s"
+---
+`, title, slug)
+}
+
+// pageStringField 读取页面前置数据中的字符串字段，文件不存在或无法解析时返回空串
+func pageStringField(filePath, key string) string {
+	if !utils.FileExists(filePath) {
+		return ""
+	}
+	doc, err := frontmatter.ReadFile(filePath)
+	if err != nil {
+		return ""
+	}
+	value, _, err := doc.GetString(key)
+	if err != nil {
+		return ""
+	}
+	return value
 }
@@ -0,0 +1,193 @@
+package generator
+
+import (
+	"hugo-content-suite/config"
+	"hugo-content-suite/manifest"
+	"hugo-content-suite/models"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateContentUsesTaxonomyTemplate(t *testing.T) {
+	template := filepath.Join(t.TempDir(), "series.md")
+	if err := os.WriteFile(template, []byte("---\ntitle: \"{title}\"\nlayout: series\n---\n{name}（{title.en}）共 {count} 篇\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	taxonomy := config.Taxonomy{Singular: "series", Plural: "series", TaxonomySettings: config.TaxonomySettings{Template: template}}
+	generator := NewTermPageGenerator(t.TempDir(), taxonomy)
+
+	preview := TermPagePreview{Term: "入门", Title: "入门", Slug: "getting-started", ArticleCount: 3, Titles: map[string]string{"zh": "入门", "en": "Getting Started"}}
+	content, err := generator.GenerateContent(preview)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "---\ntitle: \"入门\"\nlayout: series\nslug: \"getting-started\"\n---\n入门（Getting Started）共 3 篇\n"
+	if content != want {
+		t.Fatalf("content=%q", content)
+	}
+
+	generator.taxonomy.Template = ""
+	if content, _ := generator.GenerateContent(preview); content != termPageContent("入门", "getting-started") {
+		t.Fatalf("未配置模板时应使用内置内容: %q", content)
+	}
+}
+
+func TestWritePageOnlyUpdatesManagedFields(t *testing.T) {
+	dir := t.TempDir()
+	taxonomy := config.Taxonomy{Singular: "tag", Plural: "tags", TaxonomySettings: config.TaxonomySettings{Dir: dir}}
+	generator := NewTermPageGenerator(dir, taxonomy)
+	page := filepath.Join(dir, "机器学习", "_index.md")
+
+	if _, err := generator.writePage(TermPagePreview{Title: "机器学习", Slug: "ml", IndexFile: page}); err != nil {
+		t.Fatal(err)
+	}
+	edited := "---\ntitle: \"机器学习\"\nslug: \"ml\"\ndescription: 手写的介绍\nimage: cover.png\n---\n\n正文介绍\n"
+	if err := os.WriteFile(page, []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if termPageModified(page, manifest.TermPageKind("tag")) {
+		t.Fatal("只改动其他字段和正文不算手动修改")
+	}
+
+	if _, err := generator.writePage(TermPagePreview{Title: "机器学习", Slug: "machine-learning", IndexFile: page, Status: "update"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(page)
+	if string(data) != "---\ntitle: \"机器学习\"\nslug: \"machine-learning\"\ndescription: 手写的介绍\nimage: cover.png\n---\n\n正文介绍\n" {
+		t.Fatalf("更新应只改写 slug: %q", data)
+	}
+
+	// 手动改过 slug 后默认跳过
+	if err := os.WriteFile(page, []byte("---\ntitle: \"机器学习\"\nslug: \"ml-intro\"\n---\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generator.writePage(TermPagePreview{Title: "机器学习", Slug: "machine-learning", IndexFile: page, Status: "update"}); err != manifest.ErrModified {
+		t.Fatalf("手动修改的 slug 应按冲突策略处理: %v", err)
+	}
+}
+
+func TestTermPagesUseUrlizedDirsAndReportConflicts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "Golang"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, legacy := range []string{"机器学习", "Deep Learning"} {
+		if err := os.MkdirAll(filepath.Join(dir, legacy), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, legacy, "_index.md"), []byte("---\ntitle: "+legacy+"\n---\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for title, want := range map[string]string{
+		"C++":        "c++",
+		"问题?":        "问题",
+		"Go: 入门":     "go-入门",
+		"Deep  Dive": "deep-dive",
+	} {
+		if page := termPage(title, "", title, dir); page.IndexFile != filepath.Join(dir, want, "_index.md") || page.Conflict != "" {
+			t.Errorf("%q → %s (%s)", title, page.IndexFile, page.Conflict)
+		}
+	}
+	// Hugo 按 ci/cd、.net-core 查找这些词条，改写到其他目录会成为孤立页面，只能报告冲突
+	for _, title := range []string{"？", "CI/CD", ".NET Core", "nul", "etc."} {
+		if page := termPage(title, "", title, dir); page.Conflict == "" {
+			t.Errorf("%q 应标记为冲突: %s", title, page.IndexFile)
+		}
+	}
+
+	groups := [][]TermPagePreview{
+		{termPage("Go 语言", "en", "Go", dir)},
+		{termPage("围棋", "en", "go", dir)},
+		{termPage("golang", "", "golang", dir)},
+		{termPage("机器学习", "", "机器学习", dir)},
+		{termPage("深度学习", "", "深度学习", dir)},
+		{termPage("Deep Learning", "", "Deep Learning", dir)},
+	}
+	markDirConflicts(groups)
+	for i, want := range []bool{true, true, true, false, false, true} {
+		if got := groups[i][0].Conflict != ""; got != want {
+			t.Errorf("%s: conflict=%q", groups[i][0].Term, groups[i][0].Conflict)
+		}
+	}
+}
+
+func TestTermStatsMergeTermsWithTheSameURL(t *testing.T) {
+	generator := NewTermPageGenerator(t.TempDir(), config.Taxonomy{Singular: "tag", Plural: "tags"})
+	articles := []models.Article{
+		{FilePath: "a.md", Tags: []string{"Go", "go"}},
+		{FilePath: "b.md", Tags: []string{"go"}},
+		{FilePath: "c.md", Tags: []string{"go", "Rust"}},
+	}
+	stats := generator.calculateTermStats(articles)
+	if len(stats) != 2 || stats[0].Name != "go" || stats[0].Count != 3 || len(stats[0].Files) != 3 || stats[1].Name != "Rust" {
+		t.Fatalf("stats=%+v", stats)
+	}
+}
+
+func TestPreparePagesPlacesTranslatedTermPagesUnderTranslatedDirs(t *testing.T) {
+	// 模型服务只需应答连接测试，词条译名来自缓存
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"OK"}}]}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	contentDir := filepath.Join(dir, "content")
+	tagsDir := filepath.Join(contentDir, "tags")
+	if err := os.MkdirAll(filepath.Join(contentDir, "post"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(contentDir, "post", "index.md"), []byte("---\ntitle: 文章\ntags: [机器学习]\n---\n正文\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tagCache := filepath.Join(dir, "tags.json")
+	if err := os.WriteFile(tagCache, []byte(`{"en:机器学习":{"translation":"Machine Learning","type":"tag"},"ja:机器学习":{"translation":"機械学習","type":"tag"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := config.GetGlobalConfig()
+	defer config.SetGlobalConfig(previous)
+	cfg := *previous
+	cfg.Models = nil
+	cfg.LMStudio = config.LMStudioConfig{URL: server.URL, Model: "test-model", Timeout: 1}
+	cfg.Language.SourceLanguage = "zh"
+	cfg.Language.TargetLanguages = []string{"en", "ja"}
+	cfg.Paths.TagsDir = tagsDir
+	cfg.Paths.RuntimeDir = filepath.Join(dir, "runtime")
+	cfg.Paths.ContentLayout = "filename"
+	cfg.Cache.TagFileName = tagCache
+	cfg.Cache.ArticleFileName = filepath.Join(dir, "slugs.json")
+	cfg.Cache.CategoryFileName = filepath.Join(dir, "categories.json")
+	config.SetGlobalConfig(&cfg)
+
+	taxonomy, _ := cfg.Taxonomy("tag")
+	generator := NewTermPageGenerator(contentDir, taxonomy)
+	generator.SetSlugEngine(config.SlugEnginePinyin)
+	previews, createCount, _ := generator.PreparePages()
+
+	want := map[string]string{
+		"":   filepath.Join(tagsDir, "机器学习", "_index.md"),
+		"en": filepath.Join(tagsDir, "machine-learning", "_index.en.md"),
+		"ja": filepath.Join(tagsDir, "機械学習", "_index.ja.md"),
+	}
+	if len(previews) != len(want) || createCount != len(want) {
+		t.Fatalf("previews=%+v", previews)
+	}
+	slug := pinyinSlug("机器学习")
+	for _, preview := range previews {
+		if preview.IndexFile != want[preview.Lang] || preview.Status != "create" {
+			t.Errorf("[%s] %s (%s)", preview.Lang, preview.IndexFile, preview.Status)
+		}
+		if preview.Slug != slug {
+			t.Errorf("[%s] slug=%q，各语言应共用 %q", preview.Lang, preview.Slug, slug)
+		}
+		if preview.Titles["zh"] != "机器学习" || preview.Titles["en"] != "Machine Learning" || preview.Titles["ja"] != "機械学習" {
+			t.Errorf("[%s] titles=%v", preview.Lang, preview.Titles)
+		}
+	}
+}
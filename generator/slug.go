@@ -0,0 +1,225 @@
+package generator
+
+import (
+	"fmt"
+	"hugo-content-suite/config"
+	"hugo-content-suite/pinyin"
+	"hugo-content-suite/translator"
+	"hugo-content-suite/utils"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// slug 所用的语言，slug 规范按该语言的虚词表处理
+const (
+	translatedSlugLanguage = "en" // 模型翻译的 slug
+	pinyinSlugLanguage     = "zh" // 拼音 slug
+)
+
+// formatTranslatedSlug 把模型返回的 slug 格式化并按 slug 规范整理
+func formatTranslatedSlug(slug string) string {
+	return config.GetGlobalConfig().Slug.Apply(utils.FormatSlugField(slug), translatedSlugLanguage)
+}
+
+// pinyinSlug 返回文本的拼音 slug，按 slug 规范整理
+func pinyinSlug(text string) string {
+	return config.GetGlobalConfig().Slug.Apply(utils.FormatSlugField(pinyin.Slug(text)), pinyinSlugLanguage)
+}
+
+// SlugLanguage 返回 slug 所用的语言，用于按语言查找 slug 规范中的虚词：每个单词都是拼音音节
+// 或来源文本 text 中原有的字母数字单词时为拼音（zh），否则为模型翻译的英文（en）。
+// 拼音中的 a、an 等音节因此不会被当作英文虚词
+func SlugLanguage(slug, text string) string {
+	words := make(map[string]bool)
+	for _, word := range pinyin.Words(text) {
+		words[word] = true
+	}
+	for _, word := range strings.Split(slug, "-") {
+		if word != "" && !pinyin.IsSyllable(word) && !words[word] {
+			return translatedSlugLanguage
+		}
+	}
+	return pinyinSlugLanguage
+}
+
+// connectModel 测试模型服务连接并打印结果
+func connectModel(translationUtils *translator.TranslationUtils) bool {
+	fmt.Print("🔗 测试LM Studio连接... ")
+	if err := translationUtils.TestConnection(); err != nil {
+		fmt.Printf("❌ 失败 (%v)\n", err)
+		return false
+	}
+	fmt.Println("✅ 成功")
+	return true
+}
+
+// slugEngineFor 返回本次实际使用的 slug 生成方式：选择了模型但连接失败时，
+// 按 slug.pinyin_fallback 改用拼音，不允许回退时返回错误
+func slugEngineFor(engine string, connected bool) (string, error) {
+	if engine == config.SlugEnginePinyin || connected {
+		return engine, nil
+	}
+	if config.GetGlobalConfig().Slug.PinyinFallback {
+		fmt.Println("⚠️  无法连接AI翻译，改用拼音生成 slug")
+		return config.SlugEnginePinyin, nil
+	}
+	fmt.Println("⚠️ 无法连接AI翻译，终止操作")
+	return "", fmt.Errorf("AI翻译连接失败")
+}
+
+// generateSlugs 按生成方式为 texts 生成格式化且符合 slug 规范的 slug。
+// 使用模型时调用 translate 批量翻译（带缓存），拼音方式离线转写
+func generateSlugs(texts []string, engine string, translate func([]string) (map[string]string, error)) (map[string]string, error) {
+	slugMap := make(map[string]string, len(texts))
+	if engine == config.SlugEnginePinyin {
+		for _, text := range texts {
+			slugMap[text] = pinyinSlug(text)
+		}
+		return slugMap, nil
+	}
+	translated, err := translate(texts)
+	if err != nil {
+		return nil, err
+	}
+	for text, slug := range translated {
+		slugMap[text] = formatTranslatedSlug(slug)
+	}
+	return slugMap, nil
+}
+
+// slugClaim 是同一范围（栏目或分类法）内需要 slug 的一项
+type slugClaim struct {
+	Owner    string    // 文章路径或词条名，用于提示
+	Proposed string    // 翻译得到的 slug
+	Current  string    // 文件中现有的 slug
+	Date     time.Time // 发布日期，较早的一项保留原 slug
+}
+
+// slugResolution 是去重后的 slug，Collision 说明与谁重复，没有重复时为空
+type slugResolution struct {
+	Slug      string
+	Collision string
+}
+
+// resolveSlugs 保证 claims 与 taken（磁盘上不参与本次处理的 slug → 所属对象）中的 slug 互不相同。
+// 结果与处理顺序无关：现有 slug 已与翻译结果一致的项先保留；其余按日期、再按原顺序依次分配，
+// 重复时依次尝试沿用现有的日期或数字后缀 slug、日期后缀（-20240102）与数字后缀（-2、-3……）。
+// 已去重的 slug 在下次运行时得到同样的结果，不会反复变化
+func resolveSlugs(claims []slugClaim, taken map[string]string) []slugResolution {
+	used := make(map[string]string, len(taken)+len(claims))
+	for slug, owner := range taken {
+		used[slug] = owner
+	}
+	results := make([]slugResolution, len(claims))
+	done := make([]bool, len(claims))
+
+	for i, claim := range claims {
+		if claim.Proposed == "" || claim.Current != claim.Proposed {
+			continue
+		}
+		if _, ok := used[claim.Proposed]; !ok {
+			used[claim.Proposed] = claim.Owner
+			results[i] = slugResolution{Slug: claim.Proposed}
+			done[i] = true
+		}
+	}
+
+	order := make([]int, 0, len(claims))
+	for i := range claims {
+		if !done[i] {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		da, db := claims[order[a]].Date, claims[order[b]].Date
+		if da.IsZero() != db.IsZero() {
+			return !da.IsZero()
+		}
+		return da.Before(db)
+	})
+
+	for _, i := range order {
+		claim := claims[i]
+		base := claim.Proposed
+		if base == "" {
+			continue
+		}
+		owner, ok := used[base]
+		if !ok {
+			used[base] = claim.Owner
+			results[i] = slugResolution{Slug: base}
+			continue
+		}
+		slug := disambiguateSlug(base, claim, used)
+		used[slug] = claim.Owner
+		results[i] = slugResolution{Slug: slug, Collision: fmt.Sprintf("%q 与 %s 重复", base, owner)}
+	}
+	return results
+}
+
+// disambiguateSlug 为与已有 slug 重复的 base 选择一个未使用的带后缀 slug
+func disambiguateSlug(base string, claim slugClaim, used map[string]string) string {
+	free := func(slug string) bool {
+		_, ok := used[slug]
+		return !ok
+	}
+	if suffix, ok := strings.CutPrefix(claim.Current, base+"-"); ok && isDigits(suffix) && free(claim.Current) {
+		return claim.Current
+	}
+	if !claim.Date.IsZero() {
+		if slug := base + "-" + claim.Date.Format("20060102"); free(slug) {
+			return slug
+		}
+	}
+	for n := 2; ; n++ {
+		if slug := fmt.Sprintf("%s-%d", base, n); free(slug) {
+			return slug
+		}
+	}
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// contentRoot 返回 contentDir 所在的源语言内容根目录：优先使用 paths.source_content_root
+// （按语言分目录的站点，含从 Hugo 配置推导的值）；contentDir 包含标签目录时它本身就是内容根目录，
+// 否则 contentDir 是 content/post 这样的栏目目录，取其上级
+func contentRoot(cfg *config.Config, contentDir string) string {
+	if cfg.Paths.SourceContentRoot != "" {
+		return cfg.Paths.SourceContentRoot
+	}
+	if cfg.Paths.TagsDir != "" && within(contentDir, cfg.Paths.TagsDir) {
+		return contentDir
+	}
+	return filepath.Dir(absPath(contentDir))
+}
+
+// within 报告 path 是否位于 dir 之下
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(absPath(dir), absPath(path))
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// articleSection 返回文章所属的 Hugo 栏目，即相对内容根目录 root 的第一层目录。
+// 文章不在内容根目录下时返回空串，所有这样的文章视为同一范围
+func articleSection(root, path string) string {
+	rel, err := filepath.Rel(absPath(root), absPath(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	if i := strings.IndexRune(rel, filepath.Separator); i >= 0 {
+		return rel[:i]
+	}
+	return ""
+}
@@ -0,0 +1,257 @@
+package layout
+
+import (
+	"hugo-content-suite/config"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	NameAuto      = "auto"
+	NameFilename  = "filename"
+	NameDirectory = "directory"
+)
+
+// Layout 描述源文件与各语言译文文件之间的对应关系。
+// 扫描、翻译状态、删除和 slug 操作都经由它定位文件，不再各自拼接路径。
+type Layout interface {
+	Name() string
+	// Language 返回内容文件的语言：源文件为空字符串；非 Markdown 内容文件返回 ok=false。
+	Language(path string) (lang string, ok bool)
+	// TranslationPath 返回源文件在目标语言下的译文路径；无法映射时返回空字符串。
+	TranslationPath(source, lang string) string
+	// SourcePath 返回译文对应的源文件路径。
+	SourcePath(translation string) string
+	// Roots 返回扫描 contentDir 及其全部译文所需遍历的目录。
+	Roots(contentDir string) []string
+}
+
+// ForContentDir 按全局配置为内容目录选择布局。
+func ForContentDir(contentDir string) Layout {
+	return New(config.GetGlobalConfig(), contentDir)
+}
+
+// New 根据 paths.content_layout 选择布局；auto 时优先使用显式配置的语言目录，
+// 否则检测内容目录的祖先是否形如 content/<lang>/ 且存在目标语言的同级目录。
+func New(cfg *config.Config, contentDir string) Layout {
+	filename := &FilenameLayout{fileCodes: fileCodes(cfg), languages: languageCodes(cfg)}
+
+	switch cfg.Paths.ContentLayout {
+	case NameFilename:
+		return filename
+	case NameDirectory, NameAuto, "":
+		if len(cfg.Paths.LanguageContentDirs) > 0 {
+			sourceRoot := cfg.Paths.SourceContentRoot
+			if sourceRoot == "" {
+				sourceRoot = guessSourceRoot(contentDir, cfg.Paths.LanguageContentDirs)
+			}
+			return &DirectoryLayout{sourceRoot: sourceRoot, languageRoots: cfg.Paths.LanguageContentDirs, filename: filename}
+		}
+		if sourceRoot, roots := detectLanguageRoots(contentDir, filename.fileCodes, filename.languages); len(roots) > 0 {
+			return &DirectoryLayout{sourceRoot: sourceRoot, languageRoots: roots, filename: filename}
+		}
+	}
+	return filename
+}
+
+// fileCodes 返回目标语言的文件代码到语言代码的映射，文件名与语言目录都使用文件代码。
+func fileCodes(cfg *config.Config) map[string]string {
+	codes := make(map[string]string)
+	for _, lang := range cfg.Language.TargetLanguages {
+		spec, _ := cfg.Language.Spec(lang)
+		codes[strings.ToLower(spec.FileCode)] = lang
+	}
+	return codes
+}
+
+// languageCodes 返回可识别的文件代码到语言代码的映射：语言注册表中的语言与源语言，
+// 目标语言的映射优先。不在其中的后缀（如 node.js.md 的 js）不视为语言。
+func languageCodes(cfg *config.Config) map[string]string {
+	codes := make(map[string]string)
+	for code, spec := range cfg.Language.Registry() {
+		codes[strings.ToLower(spec.FileCode)] = code
+	}
+	if source := cfg.Language.SourceLanguage; source != "" {
+		spec, _ := cfg.Language.Spec(source)
+		codes[strings.ToLower(spec.FileCode)] = source
+	}
+	for code, lang := range fileCodes(cfg) {
+		codes[code] = lang
+	}
+	return codes
+}
+
+// FilenameLayout 是 Hugo 的按文件名区分语言：index.md → index.en.md，
+// _index.md → _index.en.md，foo.md → foo.en.md。覆盖叶子包、分支包与单文件页面。
+type FilenameLayout struct {
+	fileCodes map[string]string // 目标语言
+	languages map[string]string // 全部可识别的语言，见 languageCodes
+}
+
+func (l *FilenameLayout) Name() string { return NameFilename }
+
+func (l *FilenameLayout) Language(path string) (string, bool) {
+	base := filepath.Base(path)
+	if !strings.HasSuffix(base, ".md") {
+		return "", false
+	}
+	stem := strings.TrimSuffix(base, ".md")
+	if i := strings.LastIndex(stem, "."); i > 0 {
+		if lang, ok := l.languageOf(strings.ToLower(stem[i+1:])); ok {
+			return lang, true
+		}
+	}
+	return "", true
+}
+
+// languageOf 把文件代码还原为语言代码；只接受目标语言与语言注册表中的语言。
+func (l *FilenameLayout) languageOf(fileCode string) (string, bool) {
+	if lang, ok := l.fileCodes[fileCode]; ok {
+		return lang, true
+	}
+	lang, ok := l.languages[fileCode]
+	return lang, ok
+}
+
+func (l *FilenameLayout) fileCode(lang string) string {
+	for code, target := range l.fileCodes {
+		if target == lang {
+			return code
+		}
+	}
+	return lang
+}
+
+func (l *FilenameLayout) TranslationPath(source, lang string) string {
+	base := filepath.Base(source)
+	if !strings.HasSuffix(base, ".md") {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(source), strings.TrimSuffix(base, ".md")+"."+l.fileCode(lang)+".md")
+}
+
+func (l *FilenameLayout) SourcePath(translation string) string {
+	lang, ok := l.Language(translation)
+	if !ok || lang == "" {
+		return translation
+	}
+	base := filepath.Base(translation)
+	stem := strings.TrimSuffix(base, ".md")
+	return filepath.Join(filepath.Dir(translation), stem[:strings.LastIndex(stem, ".")]+".md")
+}
+
+func (l *FilenameLayout) Roots(contentDir string) []string { return []string{contentDir} }
+
+// DirectoryLayout 对应 Hugo 按语言配置 contentDir 的站点：content/zh/post/a.md
+// 的英文译文为 content/en/post/a.md，文件名本身不带语言后缀。
+type DirectoryLayout struct {
+	sourceRoot    string
+	languageRoots map[string]string
+	filename      *FilenameLayout
+}
+
+func (l *DirectoryLayout) Name() string { return NameDirectory }
+
+func (l *DirectoryLayout) Language(path string) (string, bool) {
+	if !strings.HasSuffix(path, ".md") {
+		return "", false
+	}
+	for lang, root := range l.languageRoots {
+		if within(root, path) {
+			return lang, true
+		}
+	}
+	// 源目录内若混有文件名后缀译文，也按语言识别，避免被当作源文件重复翻译。
+	return l.filename.Language(path)
+}
+
+// TranslationPath 返回语言目录中的译文路径；没有配置 contentDir 的语言与 Hugo 一样
+// 留在源目录中，按文件名后缀区分。
+func (l *DirectoryLayout) TranslationPath(source, lang string) string {
+	root, ok := l.languageRoots[lang]
+	if !ok {
+		return l.filename.TranslationPath(source, lang)
+	}
+	if !within(l.sourceRoot, source) {
+		return ""
+	}
+	rel, err := filepath.Rel(l.sourceRoot, source)
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(root, rel)
+}
+
+func (l *DirectoryLayout) SourcePath(translation string) string {
+	for _, root := range l.languageRoots {
+		if within(root, translation) {
+			rel, err := filepath.Rel(root, translation)
+			if err != nil {
+				break
+			}
+			return filepath.Join(l.sourceRoot, rel)
+		}
+	}
+	return l.filename.SourcePath(translation)
+}
+
+func (l *DirectoryLayout) Roots(contentDir string) []string {
+	roots := []string{contentDir}
+	rel, err := filepath.Rel(l.sourceRoot, contentDir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return roots
+	}
+	for _, root := range l.languageRoots {
+		roots = append(roots, filepath.Join(root, rel))
+	}
+	return roots
+}
+
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// guessSourceRoot 在 contentDir 的祖先中寻找与语言目录同级的目录，作为源语言根目录。
+func guessSourceRoot(contentDir string, languageRoots map[string]string) string {
+	for _, root := range languageRoots {
+		parent := filepath.Dir(root)
+		for dir := contentDir; ; dir = filepath.Dir(dir) {
+			if filepath.Dir(dir) == parent {
+				return dir
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+	return contentDir
+}
+
+// detectLanguageRoots 自动识别 content/<lang>/ 结构：contentDir 的某个祖先以可识别语言
+// （languages，含源语言）的文件代码命名，且其同级至少存在一个以目标语言文件代码命名的目录。
+func detectLanguageRoots(contentDir string, fileCodes, languages map[string]string) (string, map[string]string) {
+	for dir := contentDir; filepath.Dir(dir) != dir; dir = filepath.Dir(dir) {
+		if _, ok := languages[strings.ToLower(filepath.Base(dir))]; !ok {
+			continue
+		}
+		roots := make(map[string]string)
+		found := false
+		for code, lang := range fileCodes {
+			candidate := filepath.Join(filepath.Dir(dir), code)
+			if candidate == dir {
+				continue
+			}
+			roots[lang] = candidate
+			if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+				found = true
+			}
+		}
+		// 只要已有一种目标语言目录即可确认结构，尚未创建的语言目录在翻译时生成。
+		if found {
+			return dir, roots
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,116 @@
+package layout
+
+import (
+	"hugo-content-suite/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilenameLayoutMapsAllPageKinds(t *testing.T) {
+	l := New(&config.Config{Language: config.LanguageConfig{TargetLanguages: []string{"en"}}}, "content")
+	tests := map[string]string{
+		filepath.Join("post", "a", "index.md"): filepath.Join("post", "a", "index.en.md"),
+		filepath.Join("post", "_index.md"):     filepath.Join("post", "_index.en.md"),
+		filepath.Join("post", "foo.md"):        filepath.Join("post", "foo.en.md"),
+	}
+	for source, want := range tests {
+		got := l.TranslationPath(source, "en")
+		if got != want {
+			t.Fatalf("%s -> %s, want %s", source, got, want)
+		}
+		if lang, ok := l.Language(got); !ok || lang != "en" {
+			t.Fatalf("译文语言识别错误: %s -> %q", got, lang)
+		}
+		if back := l.SourcePath(got); back != source {
+			t.Fatalf("源文件反查错误: %s -> %s", got, back)
+		}
+	}
+	for _, name := range []string{"v1.2-release.md", "node.js.md", "asp.net.md", "socket.io.md"} {
+		if lang, ok := l.Language(name); !ok || lang != "" {
+			t.Fatalf("含点号的源文件 %s 被误判为 %q 译文", name, lang)
+		}
+	}
+	// 注册表中的其他语言即使不是目标语言也识别为译文，不会被当作源文件
+	if lang, ok := l.Language("foo.de.md"); !ok || lang != "de" {
+		t.Fatalf("注册表中的语言应识别为译文: %q", lang)
+	}
+}
+
+func TestAutoDetectIgnoresUnknownDirectoryNames(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "content", "doc", "post")
+	for _, dir := range []string{source, filepath.Join(root, "content", "en")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l := New(&config.Config{Language: config.LanguageConfig{SourceLanguage: "zh", TargetLanguages: []string{"en"}}}, source)
+	if l.Name() != NameFilename {
+		t.Fatalf("doc 不是语言代码，不应识别为语言目录: %s", l.Name())
+	}
+}
+
+func TestAutoDetectsLanguageContentDirs(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "content", "zh", "post")
+	for _, dir := range []string{source, filepath.Join(root, "content", "en")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l := New(&config.Config{Language: config.LanguageConfig{SourceLanguage: "zh", TargetLanguages: []string{"en", "ja"}}}, source)
+	if l.Name() != NameDirectory {
+		t.Fatalf("未识别按语言分目录的布局: %s", l.Name())
+	}
+	article := filepath.Join(source, "a", "index.md")
+	if got := l.TranslationPath(article, "ja"); got != filepath.Join(root, "content", "ja", "post", "a", "index.md") {
+		t.Fatalf("ja 译文路径错误: %s", got)
+	}
+	if lang, _ := l.Language(filepath.Join(root, "content", "en", "post", "b.md")); lang != "en" {
+		t.Fatalf("语言目录中的文件应识别为 en: %q", lang)
+	}
+	if len(l.Roots(source)) != 3 {
+		t.Fatalf("扫描根目录应包含源目录与两个语言目录: %v", l.Roots(source))
+	}
+}
+
+func TestFilenameLayoutUsesLanguageFileCode(t *testing.T) {
+	cfg := &config.Config{Language: config.LanguageConfig{
+		TargetLanguages: []string{"en", "zh-tw"},
+		Languages:       map[string]config.LanguageSpec{"zh-tw": {FileCode: "zh-hant"}},
+	}}
+	l := New(cfg, "content")
+	source := filepath.Join("post", "a", "index.md")
+	got := l.TranslationPath(source, "zh-tw")
+	if got != filepath.Join("post", "a", "index.zh-hant.md") {
+		t.Fatalf("未使用文件代码: %s", got)
+	}
+	if lang, ok := l.Language(got); !ok || lang != "zh-tw" {
+		t.Fatalf("文件代码未还原为语言代码: %q", lang)
+	}
+	if other := l.TranslationPath(source, "en"); other == got {
+		t.Fatal("不同语言不得映射到同一文件")
+	}
+}
+
+func TestDirectoryLayoutKeepsLanguagesWithoutContentDirInSourceDir(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "content", "zh")
+	cfg := &config.Config{Language: config.LanguageConfig{SourceLanguage: "zh", TargetLanguages: []string{"en", "ja"}}}
+	cfg.Paths.SourceContentRoot = source
+	cfg.Paths.LanguageContentDirs = map[string]string{"en": filepath.Join(root, "content", "en")}
+	l := New(cfg, source)
+
+	article := filepath.Join(source, "post", "a", "index.md")
+	if got := l.TranslationPath(article, "en"); got != filepath.Join(root, "content", "en", "post", "a", "index.md") {
+		t.Fatalf("en 译文路径错误: %s", got)
+	}
+	japanese := l.TranslationPath(article, "ja")
+	if japanese != filepath.Join(source, "post", "a", "index.ja.md") {
+		t.Fatalf("未配置 contentDir 的 ja 应按文件名后缀留在源目录: %s", japanese)
+	}
+	if lang, _ := l.Language(japanese); lang != "ja" || l.SourcePath(japanese) != article {
+		t.Fatalf("ja 译文识别错误: %q %s", lang, l.SourcePath(japanese))
+	}
+}
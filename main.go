@@ -52,7 +52,7 @@ func NewInteractiveMenu(reader *bufio.Reader, contentDir string, cfg *config.Con
 func (m *InteractiveMenu) Show() {
 	for {
 		m.displayMainMenu()
-		choice := utils.GetChoice(m.reader, "请选择功能 (0-6): ")
+		choice := utils.GetChoice(m.reader, "请选择功能 (0-11): ")
 
 		switch choice {
 		case ".":
@@ -73,6 +73,16 @@ func (m *InteractiveMenu) Show() {
 			} else {
 				color.Green("模型连接成功: %s", m.cfg.ActiveModel)
 			}
+		case "7":
+			m.processor.ShowContentHealth()
+		case "8":
+			m.processor.ConfigureFilter(m.reader)
+		case "9":
+			m.processor.AuditConsistency(m.reader)
+		case "10":
+			m.processor.GenerateTaxonomyPages(m.reader)
+		case "11":
+			m.processor.LintSlugs()
 
 		case "0":
 			color.Green("感谢使用！再见！")
@@ -99,6 +109,11 @@ func (m *InteractiveMenu) displayMainMenu() {
 	fmt.Println("  4. 删除指定语言的文章")
 	fmt.Println("  5. 选择翻译模型")
 	fmt.Println("  6. 测试当前翻译模型")
+	fmt.Println("  7. 内容健康检查")
+	fmt.Println("  8. 设置文章筛选条件")
+	fmt.Println("  9. 多语言一致性检查")
+	fmt.Println("  10. 生成分类、系列等其他分类法页面")
+	fmt.Println("  11. 检查 slug 规范")
 	fmt.Println()
 
 	fmt.Println()
@@ -0,0 +1,200 @@
+// Package manifest 记录本工具写出的每个生成文件的内容哈希。再次覆盖前据此判断
+// 文件是否在上次生成后被人工修改，避免重新生成时冲掉人工润色。
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hugo-content-suite/config"
+	"hugo-content-suite/utils"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// fileName 是清单在 runtime_dir 中的文件名。
+const fileName = "generated_manifest.json"
+
+const version = 1
+
+// 生成内容的种类。slug 只写入文章 front matter 的一个字段，记录的是字段值而非整个文件。
+const (
+	KindTranslation = "translation"
+	KindSlug        = "slug"
+)
+
+// TermPageKind 返回分类法词条页面的种类，如 tag_page、series_page。
+func TermPageKind(singular string) string {
+	return singular + "_page"
+}
+
+// Policy 决定目标被人工修改时如何处理。
+type Policy string
+
+const (
+	PolicySkip      Policy = "skip"      // 保留人工修改，不写入（默认）
+	PolicyOverwrite Policy = "overwrite" // 覆盖人工修改
+	PolicySaveNew   Policy = "new"       // 生成结果另存为同目录的 .new 文件
+	PolicyMerge     Policy = "merge"     // 译文专用：按快照三方合并，只重新翻译源文变化的块
+)
+
+// NewSuffix 是 PolicySaveNew 写出文件的后缀，Hugo 不会把它当作内容页面。
+const NewSuffix = ".new"
+
+// ErrModified 表示目标在上次生成后被人工修改，按默认策略未写入。
+var ErrModified = errors.New("文件在上次生成后被手动修改，已跳过")
+
+// Entry 是一次生成的记录。
+type Entry struct {
+	Kind      string    `json:"kind"`
+	Hash      string    `json:"hash"`
+	WrittenAt time.Time `json:"written_at"`
+}
+
+type manifest struct {
+	Version int               `json:"version"`
+	Entries map[string]*Entry `json:"entries"`
+
+	path string
+}
+
+var (
+	mu     sync.Mutex
+	cached *manifest
+)
+
+// manifestPath 返回清单文件路径；runtime_dir 不是绝对路径时只在进程内记录。
+func manifestPath(cfg *config.Config) string {
+	if !filepath.IsAbs(cfg.Paths.RuntimeDir) {
+		return ""
+	}
+	return filepath.Join(cfg.Paths.RuntimeDir, fileName)
+}
+
+// acquire 返回当前配置对应的清单，调用方需持有 mu。
+func acquire() *manifest {
+	path := manifestPath(config.GetGlobalConfig())
+	if cached != nil && cached.path == path {
+		return cached
+	}
+	m := &manifest{Version: version, Entries: make(map[string]*Entry), path: path}
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			var stored manifest
+			// 清单损坏时无法判断人工修改，按没有记录处理
+			if json.Unmarshal(data, &stored) == nil && stored.Version == version && stored.Entries != nil {
+				m.Entries = stored.Entries
+			}
+		}
+	}
+	cached = m
+	return m
+}
+
+func (m *manifest) save() error {
+	if m.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.path)
+}
+
+func entryKey(path, kind string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if kind == KindSlug {
+		return path + "#slug"
+	}
+	return path
+}
+
+// Hash 返回内容的哈希。
+func Hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Modified 判断 path 上由本工具生成的内容是否已被修改。current 是当前内容：
+// 文件类为整个文件，slug 为字段值。没有生成记录而内容非空时同样返回 true：
+// 这些内容在开始记录清单之前就已存在（例如升级前生成后又人工润色的译文），无法确认未被修改，
+// 由冲突策略决定是否覆盖。
+func Modified(path, kind, current string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	entry, ok := acquire().Entries[entryKey(path, kind)]
+	if !ok {
+		return current != ""
+	}
+	return entry.Hash != Hash(current)
+}
+
+// FileModified 读取文件后判断是否被修改；文件不存在时返回 false，存在但没有记录时返回 true。
+func FileModified(path, kind string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return Modified(path, kind, string(data))
+}
+
+// Record 记录一次生成的内容并立即保存清单。
+func Record(path, kind, content string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	m := acquire()
+	m.Entries[entryKey(path, kind)] = &Entry{Kind: kind, Hash: Hash(content), WrittenAt: time.Now()}
+	return m.save()
+}
+
+// WriteFile 写入生成文件并记录清单。目标在上次生成后被修改时按 policy 处理：
+// 默认返回 ErrModified，PolicySaveNew 写到 path+NewSuffix 且不记录。返回实际写入的路径。
+func WriteFile(path, kind, content string, policy Policy) (string, error) {
+	if FileModified(path, kind) {
+		switch policy {
+		case PolicyOverwrite:
+		case PolicySaveNew:
+			newPath := path + NewSuffix
+			return newPath, utils.WriteFileContent(newPath, content)
+		default:
+			return "", ErrModified
+		}
+	}
+	if err := utils.WriteFileContent(path, content); err != nil {
+		return "", err
+	}
+	if err := Record(path, kind, content); err != nil {
+		return path, fmt.Errorf("更新生成清单失败: %w", err)
+	}
+	return path, nil
+}
+
+// UpdateFile 写入对生成文件的局部修改（如同步单个字段）。文件原本未被手动修改时
+// 同步更新记录，避免这次修改让它被当成手动修改；已被手动修改的文件保持原状态。
+func UpdateFile(path, kind, content string) error {
+	modified := FileModified(path, kind)
+	if err := utils.WriteFileContent(path, content); err != nil {
+		return err
+	}
+	if modified {
+		return nil
+	}
+	if err := Record(path, kind, content); err != nil {
+		return fmt.Errorf("更新生成清单失败: %w", err)
+	}
+	return nil
+}
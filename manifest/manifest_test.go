@@ -0,0 +1,79 @@
+package manifest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileProtectsManualEdits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.ja.md")
+	if _, err := WriteFile(path, KindTranslation, "生成 1", PolicySkip); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteFile(path, KindTranslation, "生成 2", PolicySkip); err != nil {
+		t.Fatalf("未被修改的生成文件应直接覆盖: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("人工润色"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if !FileModified(path, KindTranslation) {
+		t.Fatal("应检测到人工修改")
+	}
+	if _, err := WriteFile(path, KindTranslation, "生成 3", PolicySkip); !errors.Is(err, ErrModified) {
+		t.Fatalf("默认策略应拒绝覆盖，实际为 %v", err)
+	}
+
+	written, err := WriteFile(path, KindTranslation, "生成 3", PolicySaveNew)
+	if err != nil || written != path+NewSuffix {
+		t.Fatalf("另存结果为 %q, %v", written, err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "人工润色" {
+		t.Fatalf("另存不应改动原文件: %s", got)
+	}
+
+	if _, err := WriteFile(path, KindTranslation, "生成 4", PolicyOverwrite); err != nil {
+		t.Fatal(err)
+	}
+	if FileModified(path, KindTranslation) {
+		t.Fatal("覆盖后应重新记录哈希")
+	}
+}
+
+func TestSlugEntriesTrackFieldValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.md")
+	if Modified(path, KindSlug, "") || !Modified(path, KindSlug, "anything") {
+		t.Fatal("没有生成记录时，已有的 slug 应视为修改，空值不算")
+	}
+	if err := Record(path, KindSlug, "generated-slug"); err != nil {
+		t.Fatal(err)
+	}
+	if Modified(path, KindSlug, "generated-slug") || !Modified(path, KindSlug, "hand-picked") {
+		t.Fatal("slug 记录应按字段值比较")
+	}
+	if FileModified(path, KindTranslation) {
+		t.Fatal("slug 记录不应影响同一文件的其他种类")
+	}
+}
+
+func TestExistingFilesWithoutRecordCountAsModified(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.ja.md")
+	if FileModified(path, KindTranslation) {
+		t.Fatal("文件不存在时不应视为修改")
+	}
+	// 开始记录清单之前生成并润色过的译文
+	if err := os.WriteFile(path, []byte("升级前的译文"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if !FileModified(path, KindTranslation) {
+		t.Fatal("没有生成记录的已有文件应视为修改")
+	}
+	if _, err := WriteFile(path, KindTranslation, "生成 1", PolicySkip); !errors.Is(err, ErrModified) {
+		t.Fatalf("默认策略应拒绝覆盖没有记录的文件，实际为 %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "升级前的译文" {
+		t.Fatalf("不应改动原文件: %s", got)
+	}
+}
@@ -0,0 +1,82 @@
+package manifest
+
+import (
+	"encoding/json"
+	"hugo-content-suite/config"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// snapshotDir 是译文快照在 runtime_dir 中的目录。
+const snapshotDir = "translation_snapshots"
+
+// Snapshot 保存生成译文时的源文正文块与逐块机器译文，两者一一对应。
+// 三方合并以它为共同祖先，判断哪些变化来自源文件、哪些来自人工修改。
+// Fields 记录翻译时源文件中需翻译字段的值（JSON 编码），同步前置数据时据此判断源文本是否变化。
+// FieldsOnly 表示快照只有字段记录（旧译文首次同步时建立），没有可用于合并的正文基准。
+type Snapshot struct {
+	Source     []string          `json:"source"`
+	Translated []string          `json:"translated"`
+	Fields     map[string]string `json:"fields,omitempty"`
+	FieldsOnly bool              `json:"fields_only,omitempty"`
+}
+
+var (
+	snapshotMu sync.Mutex
+	// runtime_dir 不是绝对路径时快照只保存在进程内
+	memorySnapshots = make(map[string]Snapshot)
+)
+
+func snapshotPath(path string) string {
+	dir := config.GetGlobalConfig().Paths.RuntimeDir
+	if !filepath.IsAbs(dir) {
+		return ""
+	}
+	return filepath.Join(dir, snapshotDir, Hash(entryKey(path, KindTranslation))[:32]+".json")
+}
+
+// SaveSnapshot 保存 path 对应译文的快照。
+func SaveSnapshot(path string, snapshot Snapshot) error {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+
+	file := snapshotPath(path)
+	if file == "" {
+		memorySnapshots[entryKey(path, KindTranslation)] = snapshot
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// LoadSnapshot 读取 path 对应译文的快照；没有快照或内容无效时返回 false。
+func LoadSnapshot(path string) (Snapshot, bool) {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+
+	file := snapshotPath(path)
+	if file == "" {
+		snapshot, ok := memorySnapshots[entryKey(path, KindTranslation)]
+		return snapshot, ok
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return Snapshot{}, false
+	}
+	var snapshot Snapshot
+	if json.Unmarshal(data, &snapshot) != nil || len(snapshot.Source) != len(snapshot.Translated) {
+		return Snapshot{}, false
+	}
+	return snapshot, true
+}
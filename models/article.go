@@ -1,5 +1,13 @@
 package models
 
+import (
+	"strings"
+	"time"
+)
+
+// TranslationSourceHashKey 是译文 front matter 中记录源文件哈希的键。
+const TranslationSourceHashKey = "translationSourceHash"
+
 type Article struct {
 	FilePath   string
 	Title      string
@@ -7,15 +15,53 @@ type Article struct {
 	Summary    string
 	Tags       []string
 	Categories []string
-	Date       string
-	LastMod    string
-	Featured   bool
-	Draft      bool
-	Slug       string
+	// Terms 是 tags、categories 以外的分类法词条，键为前置数据中的小写键名，如 series
+	Terms map[string][]string
+	// 日期按站点时区解析，front matter 中缺失或无法解析时为零值
+	Date        time.Time
+	LastMod     time.Time
+	PublishDate time.Time
+	ExpiryDate  time.Time
+	Featured    bool
+	Draft       bool
+	Slug        string
+	Lang        string // 译文所属语言，源文件为空
+	// 源文件 front matter 与正文的哈希；译文的 TranslationSourceHash 与之不同即已过期
+	SourceHash            string
+	TranslationSourceHash string
 	// 新增：内容信息
-	FrontMatter string   // 原始前置信息
-	BodyContent []string // 分段后的正文内容
-	CharCount   int      // 正文字符数
+	FrontMatter       string   // 原始前置信息（不含分隔符）
+	FrontMatterFormat string   // 前置信息格式：yaml、toml 或 json
+	LineEnding        string   // 源文件换行风格，译文沿用
+	BOM               bool     // 源文件是否带 UTF-8 BOM
+	BodyContent       []string // 分段后的正文内容
+	BodyBlocks        []Block  // 与 BodyContent 一一对应的块分类
+	CharCount         int      // 正文字符数
+}
+
+// TaxonomyTerms 返回文章在分类法 plural（前置数据中的键）下的词条。
+func (a *Article) TaxonomyTerms(plural string) []string {
+	switch key := strings.ToLower(plural); key {
+	case "tags":
+		return a.Tags
+	case "categories":
+		return a.Categories
+	default:
+		return a.Terms[key]
+	}
+}
+
+// Published 报告文章在 now 时是否已经发布：不是草稿，且发布时间（publishDate，缺失时为 date）
+// 不晚于 now。与 Hugo 一致，没有日期的非草稿文章也会发布。
+func (a *Article) Published(now time.Time) bool {
+	if a.Draft {
+		return false
+	}
+	date := a.PublishDate
+	if date.IsZero() {
+		date = a.Date
+	}
+	return !date.After(now)
 }
 
 type TagStats struct {
@@ -0,0 +1,17 @@
+package models
+
+// BlockKind 描述正文块在翻译时的处理方式。
+type BlockKind string
+
+const (
+	BlockTranslatable BlockKind = "translatable" // 整块交给模型翻译
+	BlockPartial      BlockKind = "partial"      // 只翻译其中的文字部分，如表格单元格
+	BlockVerbatim     BlockKind = "verbatim"     // 原样保留，不调用模型
+)
+
+// Block 是按 Markdown 语法切分出的正文块，块之间在原文中以空行分隔。
+type Block struct {
+	Kind BlockKind
+	Type string // 语法类型，如 paragraph、list、table、code、math、html、shortcode、footnote
+	Text string
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayouts 覆盖 Hugo front matter 中常见的日期写法，不带时区的按站点时区解释。
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseDate 解析日期文本；loc 为空时使用本地时区。
+func ParseDate(text string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	text = strings.TrimSpace(text)
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法解析日期 %q", text)
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	DraftsInclude = ""        // 不按草稿状态筛选
+	DraftsOnly    = "only"    // 只处理草稿
+	DraftsExclude = "exclude" // 排除草稿
+)
+
+// ArticleFilter 是各项操作共用的文章筛选条件，零值表示不筛选。
+// 多个条件同时设置时取交集；Tags、Categories 内部任一匹配即可。
+type ArticleFilter struct {
+	Drafts     string
+	DateFrom   time.Time // 含当天；按 Date 比较，缺失时退回 PublishDate
+	DateTo     time.Time // 不含；零值表示不限
+	PathGlobs  []string  // 相对内容目录的路径，使用 / 分隔，支持 ** 跨目录
+	Tags       []string
+	Categories []string
+	Featured   *bool
+}
+
+// IsEmpty 判断是否未设置任何条件。
+func (f ArticleFilter) IsEmpty() bool {
+	return f.Drafts == DraftsInclude && f.DateFrom.IsZero() && f.DateTo.IsZero() &&
+		len(f.PathGlobs) == 0 && len(f.Tags) == 0 && len(f.Categories) == 0 && f.Featured == nil
+}
+
+// Match 判断文章是否满足条件，contentDir 用于计算路径通配的相对路径。
+func (f ArticleFilter) Match(article Article, contentDir string) bool {
+	switch f.Drafts {
+	case DraftsOnly:
+		if !article.Draft {
+			return false
+		}
+	case DraftsExclude:
+		if article.Draft {
+			return false
+		}
+	}
+	if f.Featured != nil && article.Featured != *f.Featured {
+		return false
+	}
+	if !f.DateFrom.IsZero() || !f.DateTo.IsZero() {
+		date := article.Date
+		if date.IsZero() {
+			date = article.PublishDate
+		}
+		if date.IsZero() || (!f.DateFrom.IsZero() && date.Before(f.DateFrom)) || (!f.DateTo.IsZero() && !date.Before(f.DateTo)) {
+			return false
+		}
+	}
+	if len(f.Tags) > 0 && !containsAny(article.Tags, f.Tags) {
+		return false
+	}
+	if len(f.Categories) > 0 && !containsAny(article.Categories, f.Categories) {
+		return false
+	}
+	if len(f.PathGlobs) > 0 {
+		rel, err := filepath.Rel(contentDir, article.FilePath)
+		if err != nil {
+			return false
+		}
+		rel = filepath.ToSlash(rel)
+		matched := false
+		for _, pattern := range f.PathGlobs {
+			if MatchGlob(pattern, rel) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply 返回满足条件的文章；条件为空时原样返回。
+func (f ArticleFilter) Apply(articles []Article, contentDir string) []Article {
+	if f.IsEmpty() {
+		return articles
+	}
+	var result []Article
+	for _, article := range articles {
+		if f.Match(article, contentDir) {
+			result = append(result, article)
+		}
+	}
+	return result
+}
+
+func containsAny(values, wanted []string) bool {
+	for _, value := range values {
+		for _, w := range wanted {
+			if strings.EqualFold(value, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MatchGlob 按 / 分段匹配路径，** 匹配零个或多个目录，其余分段遵循 path.Match。
+// 例如 "2024/**" 匹配 2024 目录下的全部文件，"**/index.md" 匹配任意层级的叶子包。
+func MatchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
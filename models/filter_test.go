@@ -0,0 +1,55 @@
+package models
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArticleFilterMatch(t *testing.T) {
+	dir := filepath.Join("content", "post")
+	featured := true
+	article := Article{
+		FilePath: filepath.Join(dir, "2024", "hello", "index.md"),
+		Tags:     []string{"Go", "Hugo"},
+		Date:     time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Featured: true,
+	}
+	tests := []struct {
+		name   string
+		filter ArticleFilter
+		want   bool
+	}{
+		{"empty", ArticleFilter{}, true},
+		{"exclude-drafts", ArticleFilter{Drafts: DraftsExclude}, true},
+		{"drafts-only", ArticleFilter{Drafts: DraftsOnly}, false},
+		{"in-range", ArticleFilter{DateFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), DateTo: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}, true},
+		{"before-range", ArticleFilter{DateFrom: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}, false},
+		{"glob", ArticleFilter{PathGlobs: []string{"2024/**"}}, true},
+		{"glob-index", ArticleFilter{PathGlobs: []string{"**/index.md"}}, true},
+		{"glob-miss", ArticleFilter{PathGlobs: []string{"2023/**"}}, false},
+		{"tag-case-insensitive", ArticleFilter{Tags: []string{"go"}}, true},
+		{"category-miss", ArticleFilter{Categories: []string{"编程"}}, false},
+		{"featured", ArticleFilter{Featured: &featured}, true},
+	}
+	for _, test := range tests {
+		if got := test.filter.Match(article, dir); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseDateUsesLocationForNaiveDates(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	naive, err := ParseDate("2024-01-02 10:00", loc)
+	if err != nil || naive.Location() != loc {
+		t.Fatalf("不带时区的日期应使用站点时区: %v, %v", naive, err)
+	}
+	offset, err := ParseDate("2024-01-02T10:00:00Z", loc)
+	if err != nil || offset.Location() != time.UTC {
+		t.Fatalf("带时区的日期应保留原时区: %v, %v", offset, err)
+	}
+	if _, err := ParseDate("2024-13-45", loc); err == nil {
+		t.Fatal("无效日期应报错")
+	}
+}
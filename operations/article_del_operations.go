@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"hugo-content-suite/scanner"
 	"os"
-	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -20,9 +19,8 @@ func (p *Processor) ScanLanguages() ([]string, error) {
 	}
 	langSet := make(map[string]struct{})
 	for _, article := range articles {
-		lang := extractLangFromPath(article.FilePath)
-		if lang != "" {
-			langSet[lang] = struct{}{}
+		if article.Lang != "" {
+			langSet[article.Lang] = struct{}{}
 		}
 	}
 	langs := make([]string, 0, len(langSet))
@@ -70,17 +68,6 @@ func (p *Processor) DeleteArticles(reader *bufio.Reader) {
 	}
 }
 
-// 提取语言代码（假设路径如 markdown/index.en.md 或 markdown\index.en.md）
-func extractLangFromPath(path string) string {
-	base := filepath.Base(path)
-	parts := strings.Split(base, ".")
-	if len(parts) < 3 {
-		return ""
-	}
-	// 形如 index.en.md，语言在倒数第二个
-	return parts[len(parts)-2]
-}
-
 func readChoice(reader *bufio.Reader, prompt string) string {
 	fmt.Print(prompt)
 	value, _ := reader.ReadString('\n')
@@ -88,17 +75,23 @@ func readChoice(reader *bufio.Reader, prompt string) string {
 }
 
 func (p *Processor) deleteArticlesByLanguage(lang string) (int, error) {
-	articles, err := scanner.ScanArticlesWithLangs(p.contentDir, true)
+	articles, issues, err := scanner.ScanArticlesWithIssues(p.contentDir, true)
 	if err != nil {
 		return 0, err
 	}
 
 	var toDelete []string
-	for _, article := range articles {
-		if extractLangFromPath(article.FilePath) == lang {
+	for _, article := range p.filter.Apply(articles, p.contentDir) {
+		if article.Lang == lang {
 			toDelete = append(toDelete, article.FilePath)
 		}
 	}
+	// front matter 损坏的译文不在扫描结果中，但同样属于该语言；设置了筛选条件时无法判断，保留不删。
+	for _, issue := range issues {
+		if issue.Skipped && issue.Lang == lang && p.filter.IsEmpty() {
+			toDelete = append(toDelete, issue.Path)
+		}
+	}
 
 	if len(toDelete) == 0 {
 		return 0, fmt.Errorf("未找到语言为 [%s] 的文章", lang)
@@ -38,3 +38,20 @@ func TestReadChoiceConsumesSingleLine(t *testing.T) {
 		t.Fatalf("got %q", got)
 	}
 }
+
+func TestDeleteArticlesByLanguageRemovesMalformedTranslations(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"index.md": "---\ntitle: test\n---", "index.en.md": "---\ntitle: [\n---"}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	count, err := NewProcessor(dir).deleteArticlesByLanguage("en")
+	if err != nil || count != 1 {
+		t.Fatalf("count=%d, err=%v", count, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "index.en.md")); !os.IsNotExist(err) {
+		t.Fatal("front matter 损坏的译文也应删除")
+	}
+}
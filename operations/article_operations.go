@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"hugo-content-suite/generator"
+	"hugo-content-suite/utils"
 
 	"github.com/fatih/color"
 )
@@ -17,13 +18,17 @@ func (p *Processor) TranslateArticles(reader *bufio.Reader) {
 	// 获取翻译状态统计
 	color.Cyan("正在分析文章翻译状态...")
 	articleTranslator := generator.NewArticleTranslator(p.contentDir)
+	articleTranslator.SetFilter(p.filter)
 	previews, createCount, updateCount, err := articleTranslator.PrepareArticleTranslations()
 	if err != nil {
 		color.Red("❌ 分析失败: %v", err)
 		return
 	}
+	reportSkipped(articleTranslator.SkippedFiles())
 
+	staleCount := countTranslationsByStatus(previews, generator.TranslationStale)
 	p.displayTranslationStats(createCount, updateCount, len(previews))
+	fmt.Printf("   ⚠️ 过期译文: %d 个\n", staleCount)
 
 	if createCount == 0 && updateCount == 0 {
 		color.Green("✅ 所有文章都已完全翻译")
@@ -31,7 +36,7 @@ func (p *Processor) TranslateArticles(reader *bufio.Reader) {
 	}
 
 	// 选择翻译模式
-	mode := p.selectPageMode("文章翻译", createCount, updateCount, reader)
+	mode := p.selectTranslationMode(createCount, staleCount, updateCount, reader)
 	if mode == "" {
 		return
 	}
@@ -39,8 +44,24 @@ func (p *Processor) TranslateArticles(reader *bufio.Reader) {
 	// 根据模式筛选预览
 	targetPreviews := filterTranslationsByMode(previews, mode)
 
+	if mode == ModeSync {
+		fmt.Printf("\n• 将把源文件前置数据的变化同步到 %d 个已有译文，不翻译正文\n", len(targetPreviews))
+		if !p.confirmExecution(reader, "\n确认开始同步？(y/n): ") {
+			color.Yellow("❌ 已取消同步")
+			return
+		}
+		if err := articleTranslator.SyncFrontMatter(targetPreviews); err != nil {
+			color.Red("❌ 同步失败: %v", err)
+		}
+		return
+	}
+
 	// 显示警告和确认
 	p.displayTranslationWarning(mode, createCount, updateCount)
+	if mode == ModeStale {
+		fmt.Printf("• 将重新翻译 %d 个源文件修改后过期的译文\n", len(targetPreviews))
+	}
+	articleTranslator.SetConflictPolicy(p.selectConflictPolicy(reader, articleTranslator.ModifiedTargets(targetPreviews), true))
 
 	if !p.confirmExecution(reader, "\n确认开始翻译？(y/n): ") {
 		color.Yellow("❌ 已取消翻译")
@@ -92,21 +113,93 @@ func (p *Processor) displayTranslationWarning(mode string, createCount, updateCo
 	}
 }
 
+// selectTranslationMode 在通用的新增、更新、全部之外提供“仅过期译文”和“仅同步前置数据”
+func (p *Processor) selectTranslationMode(createCount, staleCount, updateCount int, reader *bufio.Reader) string {
+	fmt.Println("\n🔧 请选择处理模式:")
+
+	if createCount > 0 {
+		fmt.Printf("   1. 仅新增 (%d 篇)\n", createCount)
+	}
+	if staleCount > 0 {
+		fmt.Printf("   2. 仅更新过期译文 (%d 个)\n", staleCount)
+	}
+	if updateCount > 0 {
+		fmt.Printf("   3. 更新全部已有译文 (%d 篇)\n", updateCount)
+	}
+	if createCount > 0 && updateCount > 0 {
+		fmt.Printf("   4. 全部处理 (%d 篇)\n", createCount+updateCount)
+	}
+	if updateCount > 0 {
+		fmt.Printf("   5. 仅同步前置数据，不翻译正文 (%d 篇)\n", updateCount)
+	}
+	fmt.Println("   0. 取消操作")
+
+	switch utils.GetChoice(reader, "请选择: ") {
+	case "1":
+		if createCount == 0 {
+			color.Yellow("⚠️  没有需要新增的文章翻译")
+			return ""
+		}
+		return ModeCreate
+	case "2":
+		if staleCount == 0 {
+			color.Yellow("⚠️  没有过期的译文")
+			return ""
+		}
+		return ModeStale
+	case "3":
+		if updateCount == 0 {
+			color.Yellow("⚠️  没有已有的译文")
+			return ""
+		}
+		return ModeUpdate
+	case "4":
+		return ModeAll
+	case "5":
+		if updateCount == 0 {
+			color.Yellow("⚠️  没有已有的译文")
+			return ""
+		}
+		return ModeSync
+	case "0":
+		color.Yellow("❌ 已取消操作")
+		return ""
+	default:
+		color.Red("⚠️  无效选择")
+		return ""
+	}
+}
+
+// countTranslationsByStatus 统计指定状态的翻译任务数
+func countTranslationsByStatus(previews []generator.ArticleTranslationPreview, status string) int {
+	count := 0
+	for _, preview := range previews {
+		if preview.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
 // filterTranslationsByMode 根据模式筛选翻译任务
 func filterTranslationsByMode(previews []generator.ArticleTranslationPreview, mode string) []generator.ArticleTranslationPreview {
 	var filtered []generator.ArticleTranslationPreview
 
 	for _, preview := range previews {
 		switch mode {
-		case "create":
-			if preview.Status == "missing" {
+		case ModeCreate:
+			if preview.Status == generator.TranslationMissing {
+				filtered = append(filtered, preview)
+			}
+		case ModeStale:
+			if preview.Status == generator.TranslationStale {
 				filtered = append(filtered, preview)
 			}
-		case "update":
-			if preview.Status == "update" {
+		case ModeUpdate, ModeSync:
+			if preview.Status != generator.TranslationMissing {
 				filtered = append(filtered, preview)
 			}
-		case "all":
+		case ModeAll:
 			filtered = append(filtered, preview)
 		}
 	}
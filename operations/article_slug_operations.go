@@ -4,6 +4,9 @@ import (
 	"bufio"
 	"fmt"
 	"hugo-content-suite/generator"
+	"hugo-content-suite/utils"
+	"strconv"
+	"strings"
 
 	"github.com/fatih/color"
 )
@@ -14,17 +17,30 @@ func (p *Processor) GenerateArticleSlugs(reader *bufio.Reader) {
 		return
 	}
 
+	engine := p.selectSlugEngine(reader)
+
 	// 获取文章slug状态统计
 	color.Cyan("正在分析文章slug状态...")
 	slugGenerator := generator.NewArticleSlugGenerator(p.contentDir)
+	slugGenerator.SetFilter(p.filter)
+	slugGenerator.SetSlugEngine(engine)
 	previews, createCount, updateCount, err := slugGenerator.PrepareArticleSlugs()
 	if err != nil {
 		color.Red("❌ 分析失败: %v", err)
 		return
 	}
+	reportSkipped(slugGenerator.SkippedFiles())
 
 	p.displaySlugStats(createCount, updateCount, len(previews))
 
+	// 挑选覆盖全部文章，包括 slug 已与生成结果一致的文章，挑选后按新的状态重新统计
+	var chosen map[string]bool
+	if p.confirmExecution(reader, "\n是否逐篇挑选 slug？(y/n，默认 n): ") {
+		chosen = p.reviewArticleSlugs(reader, slugGenerator, previews)
+		createCount = len(filterByMode(previews, ModeCreate))
+		updateCount = len(filterByMode(previews, ModeUpdate))
+	}
+
 	if createCount == 0 && updateCount == 0 {
 		color.Green("✅ 所有文章slug都是最新的")
 		return
@@ -35,12 +51,12 @@ func (p *Processor) GenerateArticleSlugs(reader *bufio.Reader) {
 	if mode == "" {
 		return
 	}
-
-	// 根据模式筛选预览
-	targetPreviews := filterByMode(previews, mode)
+	targetPreviews := slugTargets(previews, mode, chosen)
 
 	// 显示警告和确认
 	p.displaySlugWarning(mode, createCount, updateCount)
+	slugGenerator.SetConflictPolicy(p.selectConflictPolicy(reader, slugGenerator.ModifiedSlugs(targetPreviews), false))
+	slugGenerator.SetForceLocked(p.confirmForceLocked(reader, slugGenerator.LockedSlugs(targetPreviews)))
 
 	if !p.confirmExecution(reader, "\n确认开始生成？(y/n): ") {
 		color.Yellow("❌ 已取消生成")
@@ -53,6 +69,22 @@ func (p *Processor) GenerateArticleSlugs(reader *bufio.Reader) {
 	}
 }
 
+// slugTargets 返回需要写入的预览：符合处理模式的文章，以及挑选过且与当前 slug 不同的文章（不受模式限制），
+// 保持预览顺序
+func slugTargets(previews []generator.ArticleSlugPreview, mode string, chosen map[string]bool) []generator.ArticleSlugPreview {
+	included := make(map[string]bool)
+	for _, preview := range filterByMode(previews, mode) {
+		included[preview.FilePath] = true
+	}
+	var targets []generator.ArticleSlugPreview
+	for _, preview := range previews {
+		if included[preview.FilePath] || (chosen[preview.FilePath] && preview.Status != "skip") {
+			targets = append(targets, preview)
+		}
+	}
+	return targets
+}
+
 func (p *Processor) displaySlugStats(createCount, updateCount, total int) {
 	fmt.Printf("\n📊 Slug统计信息:\n")
 	fmt.Printf("   🆕 需要新建slug的文章: %d 篇\n", createCount)
@@ -83,3 +115,89 @@ func (p *Processor) displaySlugWarning(mode string, createCount, updateCount int
 		fmt.Printf("• 将处理 %d 篇文章的slug（包括新建和更新）\n", createCount+updateCount)
 	}
 }
+
+// confirmForceLocked 列出 slug 受 slug.lock 保护的已发布文章并询问是否强制修改，默认保持原 slug
+func (p *Processor) confirmForceLocked(reader *bufio.Reader, paths []string) bool {
+	if len(paths) == 0 {
+		return false
+	}
+
+	const maxListed = 20
+	color.Yellow("\n🔒 以下 %d 篇已发布文章的 slug 受保护，修改会改变已收录的地址:", len(paths))
+	for i, path := range paths {
+		if i == maxListed {
+			fmt.Printf("   ... 以及另外 %d 篇\n", len(paths)-maxListed)
+			break
+		}
+		fmt.Printf("   • %s\n", path)
+	}
+	fmt.Println("   强制修改时旧 slug 会写入各语言页面的 aliases，旧链接重定向到新地址")
+	if !p.confirmExecution(reader, "是否强制修改这些 slug？(y/n，默认 n): ") {
+		return false
+	}
+	color.Red("⚠️  将修改 %d 篇已发布文章的 slug", len(paths))
+	return true
+}
+
+// reviewArticleSlugs 逐篇列出候选 slug，选择编号、编辑候选（e编号）或直接输入 slug，选定后固定到 slug 缓存；
+// 直接回车保留预览中的 slug，q 结束挑选，其余文章保持预览结果。返回选定了 slug 的文章
+func (p *Processor) reviewArticleSlugs(reader *bufio.Reader, slugGenerator *generator.ArticleSlugGenerator, previews []generator.ArticleSlugPreview) map[string]bool {
+	chosen := make(map[string]bool)
+	for i := range previews {
+		preview := &previews[i]
+		color.Cyan("\n[%d/%d] %s", i+1, len(previews), preview.Title)
+		if preview.CurrentSlug != "" {
+			fmt.Printf("   当前: %s\n", preview.CurrentSlug)
+		}
+		candidates := slugGenerator.SlugCandidates(*preview)
+		for j, candidate := range candidates {
+			fmt.Printf("   %d. %s (%s)\n", j+1, candidate.Slug, candidate.Source)
+			if len(candidate.Problems) > 0 {
+				fmt.Printf("      ⚠️  %s\n", strings.Join(candidate.Problems, "；"))
+			}
+			if candidate.Collision != "" {
+				fmt.Printf("      ⛔ %s\n", candidate.Collision)
+			}
+		}
+
+		for {
+			input := utils.GetChoice(reader, fmt.Sprintf("选择编号，e编号 编辑，或输入 slug（回车保留 %s，q 结束）: ", preview.NewSlug))
+			slug := input
+			switch {
+			case input == "":
+			case input == "q":
+				return chosen
+			case strings.HasPrefix(input, "e") && candidateIndex(input[1:], len(candidates)) >= 0:
+				base := candidates[candidateIndex(input[1:], len(candidates))].Slug
+				fmt.Printf("   编辑: %s\n", base)
+				if slug = utils.GetChoice(reader, "   新的 slug（回车不改）: "); slug == "" {
+					slug = base
+				}
+			default:
+				if index := candidateIndex(input, len(candidates)); index >= 0 {
+					slug = candidates[index].Slug
+				}
+			}
+			if slug == "" {
+				break
+			}
+			if err := slugGenerator.ChooseSlug(preview, slug); err != nil {
+				color.Red("❌ %v", err)
+				continue
+			}
+			chosen[preview.FilePath] = true
+			color.Green("   📌 %s", preview.NewSlug)
+			break
+		}
+	}
+	return chosen
+}
+
+// candidateIndex 把从 1 开始的编号转换为下标，不是有效编号时返回 -1
+func candidateIndex(input string, count int) int {
+	n, err := strconv.Atoi(input)
+	if err != nil || n < 1 || n > count {
+		return -1
+	}
+	return n - 1
+}
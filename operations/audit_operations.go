@@ -0,0 +1,334 @@
+package operations
+
+import (
+	"bufio"
+	"fmt"
+	"hugo-content-suite/config"
+	"hugo-content-suite/frontmatter"
+	"hugo-content-suite/generator"
+	"hugo-content-suite/layout"
+	"hugo-content-suite/manifest"
+	"hugo-content-suite/models"
+	"hugo-content-suite/scanner"
+	"hugo-content-suite/utils"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// 一致性检查发现的问题类型
+const (
+	findingOrphan   = "orphan"    // 源文件已不存在的译文
+	findingField    = "field"     // 与源文件不一致的字段
+	findingBlocks   = "blocks"    // 块数量与源文件差异过大
+	findingConflict = "conflict"  // 含未解决的合并冲突标记
+	findingTermPage = "term_page" // 没有文章引用的分类法词条页面
+)
+
+// orphanSuffix 是孤立文件改名后的后缀，Hugo 不再把它当作内容页面，需要时改回即可恢复。
+const orphanSuffix = ".orphan"
+
+// 译文块数与源文件相差至少 blockDivergenceMin 块且超过源文块数的 blockDivergenceRatio 时报告。
+const (
+	blockDivergenceMin   = 3
+	blockDivergenceRatio = 0.3
+)
+
+// auditFinding 是一条一致性问题；fix 为空表示只报告，不提供自动修复。
+type auditFinding struct {
+	Kind   string
+	Path   string
+	Detail string
+	fix    func() error
+}
+
+// AuditConsistency 交叉检查源文件、各语言译文与分类法词条页面，报告问题并提供安全的修复。
+func (p *Processor) AuditConsistency(reader *bufio.Reader) {
+	if p.contentDir == "" {
+		color.Red("❌ 内容目录未设置")
+		return
+	}
+
+	color.Cyan("正在检查多语言内容一致性...")
+	findings, err := p.collectAuditFindings()
+	if err != nil {
+		color.Red("❌ 检查失败: %v", err)
+		return
+	}
+	if len(findings) == 0 {
+		color.Green("✅ 未发现不一致")
+		return
+	}
+
+	titles := map[string]string{
+		findingOrphan:   "孤立译文（源文件已不存在）",
+		findingField:    "与源文件不一致的字段",
+		findingBlocks:   "段落结构与源文件差异较大",
+		findingConflict: "未解决的合并冲突",
+		findingTermPage: "没有文章引用的词条页面（标签、分类等）",
+	}
+	order := []string{findingOrphan, findingField, findingBlocks, findingConflict, findingTermPage}
+	fixable := make(map[string][]auditFinding)
+
+	color.Cyan("\n📋 一致性报告")
+	for _, kind := range order {
+		var group []auditFinding
+		for _, finding := range findings {
+			if finding.Kind == kind {
+				group = append(group, finding)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		color.Yellow("\n%s: %d 项", titles[kind], len(group))
+		for _, finding := range group {
+			fmt.Printf("  • %s\n", p.relativePath(finding.Path))
+			if finding.Detail != "" {
+				fmt.Printf("     %s\n", finding.Detail)
+			}
+			if finding.fix != nil {
+				fixable[kind] = append(fixable[kind], finding)
+			}
+		}
+	}
+
+	if len(fixable) == 0 {
+		return
+	}
+
+	fmt.Println("\n🔧 可执行的修复:")
+	if n := len(fixable[findingField]); n > 0 {
+		fmt.Printf("   1. 用源文件的值同步不一致字段 (%d 项)\n", n)
+	}
+	if n := len(fixable[findingOrphan]); n > 0 {
+		fmt.Printf("   2. 孤立译文改名为 *%s，不再发布 (%d 个)\n", orphanSuffix, n)
+	}
+	if n := len(fixable[findingTermPage]); n > 0 {
+		fmt.Printf("   3. 未引用的词条页面改名为 *%s (%d 个)\n", orphanSuffix, n)
+	}
+	fmt.Println("   0. 不修复")
+
+	var selected []auditFinding
+	switch utils.GetChoice(reader, "请选择: ") {
+	case "1":
+		selected = fixable[findingField]
+	case "2":
+		selected = fixable[findingOrphan]
+	case "3":
+		selected = fixable[findingTermPage]
+	default:
+		return
+	}
+	if len(selected) == 0 {
+		color.Yellow("⚠️  没有可修复的项目")
+		return
+	}
+	if !p.confirmExecution(reader, fmt.Sprintf("确认修复 %d 项？(y/n): ", len(selected))) {
+		color.Yellow("❌ 已取消修复")
+		return
+	}
+
+	fixed := 0
+	for _, finding := range selected {
+		if err := finding.fix(); err != nil {
+			color.Red("  ❌ %s: %v", p.relativePath(finding.Path), err)
+			continue
+		}
+		fixed++
+	}
+	color.Green("✅ 已修复 %d/%d 项", fixed, len(selected))
+}
+
+func (p *Processor) relativePath(path string) string {
+	if rel, err := filepath.Rel(p.contentDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// collectAuditFindings 扫描源文件与全部译文并汇总问题。筛选条件作用于源文件，
+// 译文随其源文件一起检查；孤立译文与标签页面不受筛选影响。
+func (p *Processor) collectAuditFindings() ([]auditFinding, error) {
+	articles, _, err := scanner.ScanArticlesWithIssues(p.contentDir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	contentLayout := layout.ForContentDir(p.contentDir)
+	sources := make(map[string]models.Article)
+	var translations []models.Article
+	for _, article := range articles {
+		if article.Lang == "" {
+			sources[article.FilePath] = article
+		} else {
+			translations = append(translations, article)
+		}
+	}
+	sort.Slice(translations, func(i, j int) bool { return translations[i].FilePath < translations[j].FilePath })
+
+	var findings []auditFinding
+	for _, translation := range translations {
+		sourcePath := contentLayout.SourcePath(translation.FilePath)
+		source, ok := sources[sourcePath]
+		if !ok {
+			// 源文件存在但无法解析时不算孤立，由健康检查报告
+			if !utils.FileExists(sourcePath) {
+				path := translation.FilePath
+				findings = append(findings, auditFinding{
+					Kind:   findingOrphan,
+					Path:   path,
+					Detail: fmt.Sprintf("找不到源文件 %s", p.relativePath(sourcePath)),
+					fix:    func() error { return os.Rename(path, path+orphanSuffix) },
+				})
+			}
+			continue
+		}
+		if !p.filter.IsEmpty() && !p.filter.Match(source, p.contentDir) {
+			continue
+		}
+		findings = append(findings, compareVariant(source, translation)...)
+	}
+
+	for _, taxonomy := range config.GetGlobalConfig().TaxonomyList() {
+		termFindings, err := p.unreferencedTermPages(taxonomy, articles)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, termFindings...)
+	}
+	return findings, nil
+}
+
+// compareVariant 比较译文与源文件的发布字段和正文结构。
+func compareVariant(source, translation models.Article) []auditFinding {
+	var findings []auditFinding
+	path := translation.FilePath
+
+	fieldFinding := func(key string, sourceValue, variantValue interface{}, fixable bool) {
+		finding := auditFinding{
+			Kind:   findingField,
+			Path:   path,
+			Detail: fmt.Sprintf("%s: 源文件为 %v，译文为 %v", key, displayValue(sourceValue), displayValue(variantValue)),
+		}
+		if fixable {
+			finding.fix = func() error { return setVariantField(path, key, sourceValue) }
+		}
+		findings = append(findings, finding)
+	}
+	if source.Slug != translation.Slug {
+		fieldFinding("slug", source.Slug, translation.Slug, source.Slug != "")
+	}
+	if !source.Date.Equal(translation.Date) {
+		fieldFinding("date", source.Date, translation.Date, !source.Date.IsZero())
+	}
+	if source.Draft != translation.Draft {
+		fieldFinding("draft", source.Draft, translation.Draft, true)
+	}
+	if source.Featured != translation.Featured {
+		fieldFinding("featured", source.Featured, translation.Featured, true)
+	}
+
+	if scanner.LoadContent(&source) != nil || scanner.LoadContent(&translation) != nil {
+		return findings
+	}
+	sourceBlocks, variantBlocks := len(source.BodyBlocks), len(translation.BodyBlocks)
+	diff := sourceBlocks - variantBlocks
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff >= blockDivergenceMin && float64(diff) > float64(sourceBlocks)*blockDivergenceRatio {
+		findings = append(findings, auditFinding{
+			Kind:   findingBlocks,
+			Path:   path,
+			Detail: fmt.Sprintf("源文件 %d 块，译文 %d 块，可能漏译或结构被改动", sourceBlocks, variantBlocks),
+		})
+	}
+	for _, block := range translation.BodyContent {
+		if strings.Contains(block, generator.ConflictMarker) {
+			findings = append(findings, auditFinding{Kind: findingConflict, Path: path, Detail: "正文中仍有冲突标记"})
+			break
+		}
+	}
+	return findings
+}
+
+func displayValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return "（未设置）"
+		}
+		return v
+	case interface{ IsZero() bool }:
+		if v.IsZero() {
+			return "（未设置）"
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// setVariantField 把源文件的值写入译文。
+func setVariantField(path, key string, value interface{}) error {
+	doc, err := frontmatter.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := doc.SetValue(key, value); err != nil {
+		return err
+	}
+	return manifest.UpdateFile(path, manifest.KindTranslation, doc.String())
+}
+
+// unreferencedTermPages 列出分类法目录中没有任何文章使用的词条目录。各语言的词条页面位于
+// 词条译名对应的目录，因此源文件与译文中的词条都算作引用；目录名按 urlize 后的名称匹配，
+// 也兼容以原词条命名的旧目录。
+func (p *Processor) unreferencedTermPages(taxonomy config.Taxonomy, articles []models.Article) ([]auditFinding, error) {
+	if taxonomy.Dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(taxonomy.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := make(map[string]bool)
+	for _, article := range articles {
+		for _, term := range article.TaxonomyTerms(taxonomy.Plural) {
+			referenced[strings.ToLower(term)] = true
+			referenced[utils.TermDirName(term)] = true
+		}
+	}
+
+	var findings []auditFinding
+	for _, entry := range entries {
+		if !entry.IsDir() || referenced[strings.ToLower(entry.Name())] {
+			continue
+		}
+		dir := filepath.Join(taxonomy.Dir, entry.Name())
+		pages, _ := filepath.Glob(filepath.Join(dir, "_index*.md"))
+		if len(pages) == 0 {
+			continue
+		}
+		findings = append(findings, auditFinding{
+			Kind:   findingTermPage,
+			Path:   dir,
+			Detail: fmt.Sprintf("没有文章使用%s %q，共 %d 个页面文件", taxonomy.Label(), entry.Name(), len(pages)),
+			fix: func() error {
+				for _, page := range pages {
+					if err := os.Rename(page, page+orphanSuffix); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		})
+	}
+	return findings, nil
+}
@@ -0,0 +1,54 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditFindsOrphanAndSyncsFields(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a/index.md":    "---\ntitle: 源文\nslug: hello\ndraft: true\n---\n正文\n",
+		"a/index.en.md": "---\ntitle: Source\nslug: old\ndraft: false\n---\nBody\n",
+		"b/index.en.md": "---\ntitle: Orphan\n---\nBody\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	findings, err := NewProcessor(dir).collectAuditFindings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		counts[finding.Kind]++
+		if finding.Kind == findingField || finding.Kind == findingOrphan {
+			if err := finding.fix(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if counts[findingOrphan] != 1 || counts[findingField] != 2 {
+		t.Fatalf("findings=%v", counts)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "a/index.en.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "slug: \"hello\"") || !strings.Contains(string(data), "draft: true") {
+		t.Fatalf("字段未同步: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b/index.en.md"+orphanSuffix)); err != nil {
+		t.Fatal("孤立译文应改名保留")
+	}
+}
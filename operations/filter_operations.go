@@ -0,0 +1,109 @@
+package operations
+
+import (
+	"bufio"
+	"fmt"
+	"hugo-content-suite/config"
+	"hugo-content-suite/models"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// FilterFromConfig 把配置中的文本条件转换为筛选条件。只写日期的 date_to 包含当天。
+func FilterFromConfig(fc config.FilterConfig, loc *time.Location) (models.ArticleFilter, error) {
+	filter := models.ArticleFilter{
+		PathGlobs:  fc.Paths,
+		Tags:       fc.Tags,
+		Categories: fc.Categories,
+		Featured:   fc.Featured,
+	}
+	switch fc.Drafts {
+	case models.DraftsInclude, models.DraftsOnly, models.DraftsExclude:
+		filter.Drafts = fc.Drafts
+	default:
+		return models.ArticleFilter{}, fmt.Errorf("drafts 只能为空、only 或 exclude，实际为 %q", fc.Drafts)
+	}
+	if fc.DateFrom != "" {
+		from, err := models.ParseDate(fc.DateFrom, loc)
+		if err != nil {
+			return models.ArticleFilter{}, fmt.Errorf("date_from: %w", err)
+		}
+		filter.DateFrom = from
+	}
+	if fc.DateTo != "" {
+		to, err := models.ParseDate(fc.DateTo, loc)
+		if err != nil {
+			return models.ArticleFilter{}, fmt.Errorf("date_to: %w", err)
+		}
+		if len(strings.TrimSpace(fc.DateTo)) == len("2006-01-02") {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.DateTo = to
+	}
+	return filter, nil
+}
+
+// ConfigureFilter 交互式修改本次运行的筛选条件：回车保留当前值，输入 - 清除。
+func (p *Processor) ConfigureFilter(reader *bufio.Reader) {
+	cfg := config.GetGlobalConfig()
+	fc := cfg.Filter
+
+	color.Cyan("\n🔍 设置文章筛选条件（回车保留，- 清除）")
+	fc.Drafts = promptFilterValue(reader, "草稿 (only/exclude)", fc.Drafts)
+	fc.DateFrom = promptFilterValue(reader, "起始日期 (含)", fc.DateFrom)
+	fc.DateTo = promptFilterValue(reader, "截止日期 (含)", fc.DateTo)
+	fc.Paths = splitFilterList(promptFilterValue(reader, "路径通配 (逗号分隔)", strings.Join(fc.Paths, ",")))
+	fc.Tags = splitFilterList(promptFilterValue(reader, "标签 (逗号分隔)", strings.Join(fc.Tags, ",")))
+	fc.Categories = splitFilterList(promptFilterValue(reader, "分类 (逗号分隔)", strings.Join(fc.Categories, ",")))
+	featured := ""
+	if fc.Featured != nil {
+		featured = fmt.Sprint(*fc.Featured)
+	}
+	switch promptFilterValue(reader, "精选 (true/false)", featured) {
+	case "true":
+		value := true
+		fc.Featured = &value
+	case "false":
+		value := false
+		fc.Featured = &value
+	default:
+		fc.Featured = nil
+	}
+
+	filter, err := FilterFromConfig(fc, cfg.Location())
+	if err != nil {
+		color.Red("❌ 筛选条件无效: %v", err)
+		return
+	}
+	cfg.Filter = fc
+	p.filter = filter
+	if filter.IsEmpty() {
+		color.Green("✅ 已清除筛选条件，将处理全部文章")
+	} else {
+		color.Green("✅ 筛选条件已生效，后续操作只处理满足条件的文章")
+	}
+}
+
+func promptFilterValue(reader *bufio.Reader, label, current string) string {
+	input := strings.TrimSpace(readChoice(reader, fmt.Sprintf("  %s [%s]: ", label, current)))
+	switch input {
+	case "":
+		return current
+	case "-":
+		return ""
+	default:
+		return input
+	}
+}
+
+func splitFilterList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package operations
+
+import (
+	"fmt"
+	"hugo-content-suite/scanner"
+	"path/filepath"
+	"sort"
+
+	"github.com/fatih/color"
+)
+
+// ShowContentHealth 扫描源文件与全部译文，列出 front matter 无法解析或字段无效的文件。
+func (p *Processor) ShowContentHealth() {
+	if p.contentDir == "" {
+		color.Red("❌ 内容目录未设置")
+		return
+	}
+
+	color.Cyan("正在检查内容健康状况...")
+	articles, issues, err := scanner.ScanArticlesWithIssues(p.contentDir, true)
+	if err != nil {
+		color.Red("❌ 扫描失败: %v", err)
+		return
+	}
+
+	if len(issues) == 0 {
+		color.Green("✅ 共检查 %d 个文件，未发现问题", len(articles))
+		return
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Skipped != issues[j].Skipped {
+			return issues[i].Skipped
+		}
+		return issues[i].Path < issues[j].Path
+	})
+
+	skipped := 0
+	color.Cyan("\n📋 内容健康报告")
+	for _, issue := range issues {
+		path := issue.Path
+		if rel, err := filepath.Rel(p.contentDir, issue.Path); err == nil {
+			path = rel
+		}
+		if issue.Skipped {
+			skipped++
+			color.Red("  ❌ %s", path)
+		} else {
+			color.Yellow("  ⚠️  %s", path)
+		}
+		fmt.Printf("     %s\n", issue.Reason)
+	}
+
+	fmt.Println()
+	fmt.Printf("共检查 %d 个文件：%d 个无法解析（已跳过），%d 个字段问题\n", len(articles)+skipped, skipped, len(issues)-skipped)
+}
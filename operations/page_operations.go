@@ -2,43 +2,99 @@ package operations
 
 import (
 	"bufio"
+	"fmt"
+	"hugo-content-suite/config"
 	"hugo-content-suite/generator"
+	"hugo-content-suite/utils"
+	"strconv"
 
 	"github.com/fatih/color"
 )
 
+// GenerateTagPages 生成标签页面
 func (p *Processor) GenerateTagPages(reader *bufio.Reader) {
+	taxonomy, ok := config.GetGlobalConfig().Taxonomy("tag")
+	if !ok {
+		color.Yellow("⚠️  站点没有启用 tag 分类法")
+		return
+	}
+	p.GenerateTermPages(reader, taxonomy)
+}
+
+// GenerateTaxonomyPages 选择标签以外的分类法（分类、系列等）并生成词条页面
+func (p *Processor) GenerateTaxonomyPages(reader *bufio.Reader) {
+	taxonomies := otherTaxonomies()
+	if len(taxonomies) == 0 {
+		color.Yellow("⚠️  除标签外没有配置其他分类法")
+		return
+	}
+	if len(taxonomies) == 1 {
+		p.GenerateTermPages(reader, taxonomies[0])
+		return
+	}
+
+	fmt.Println("\n可用分类法：")
+	for i, taxonomy := range taxonomies {
+		fmt.Printf("  %d. %s (%s)\n", i+1, taxonomy.Label(), taxonomy.Plural)
+	}
+	choice := utils.GetChoice(reader, "选择分类法编号（0 取消）: ")
+	index, err := strconv.Atoi(choice)
+	if err != nil || index < 1 || index > len(taxonomies) {
+		return
+	}
+	p.GenerateTermPages(reader, taxonomies[index-1])
+}
+
+// GenerateTermPages 预览并生成分类法 taxonomy 的词条页面
+func (p *Processor) GenerateTermPages(reader *bufio.Reader, taxonomy config.Taxonomy) {
 	if p.contentDir == "" {
 		color.Red("❌ 内容目录未设置")
 		return
 	}
+	label := taxonomy.Label() + "页面"
+	engine := p.selectSlugEngine(reader)
 
 	// 先预览以获取统计信息
-	color.Cyan("正在分析标签页面状态...")
-	pageGenerator := generator.NewTagPageGenerator(p.contentDir)
-	previews, createCount, updateCount := pageGenerator.PrepareTagPages()
+	color.Cyan("正在分析%s状态...", label)
+	pageGenerator := generator.NewTermPageGenerator(p.contentDir, taxonomy)
+	pageGenerator.SetFilter(p.filter)
+	pageGenerator.SetSlugEngine(engine)
+	previews, createCount, updateCount := pageGenerator.PreparePages()
+	reportSkipped(pageGenerator.SkippedFiles())
 
 	if createCount == 0 && updateCount == 0 {
-		color.Green("✅ 所有标签页面都是最新的")
+		color.Green("✅ 所有%s都是最新的", label)
 		return
 	}
 
 	// 选择处理模式
-	mode := p.selectPageMode(TagPageLabel, createCount, updateCount, reader)
+	mode := p.selectPageMode(label, createCount, updateCount, reader)
 	if mode == "" {
 		return
 	}
 
 	// 根据模式筛选预览（使用通用函数）
 	targetPreviews := filterByMode(previews, mode)
+	pageGenerator.SetConflictPolicy(p.selectConflictPolicy(reader, pageGenerator.ModifiedPages(targetPreviews), false))
 
 	if !p.confirmExecution(reader, "\n确认执行？(y/n): ") {
 		color.Yellow("❌ 已取消生成")
 		return
 	}
 
-	color.Cyan("🚀 正在生成标签页面...")
-	if err := pageGenerator.GenerateTagPagesWithMode(targetPreviews, mode); err != nil {
+	color.Cyan("🚀 正在生成%s...", label)
+	if err := pageGenerator.GeneratePagesWithMode(targetPreviews, mode); err != nil {
 		color.Red("❌ 生成失败: %v", err)
 	}
 }
+
+// otherTaxonomies 返回标签以外的分类法
+func otherTaxonomies() []config.Taxonomy {
+	var result []config.Taxonomy
+	for _, taxonomy := range config.GetGlobalConfig().TaxonomyList() {
+		if taxonomy.Singular != "tag" {
+			result = append(result, taxonomy)
+		}
+	}
+	return result
+}
@@ -3,7 +3,10 @@ package operations
 import (
 	"bufio"
 	"fmt"
+	"hugo-content-suite/config"
 	"hugo-content-suite/generator"
+	"hugo-content-suite/manifest"
+	"hugo-content-suite/models"
 	"hugo-content-suite/utils"
 	"strings"
 	"time"
@@ -18,7 +21,9 @@ const (
 
 	ModeUpdate = "update"
 	ModeCreate = "create"
+	ModeStale  = "stale" // 仅重新翻译源文件修改后过期的译文
 	ModeAll    = "all"
+	ModeSync   = "sync" // 仅把源文件前置数据的变化同步到已有译文，不翻译正文
 )
 
 type StatusLike interface {
@@ -27,11 +32,18 @@ type StatusLike interface {
 
 type Processor struct {
 	contentDir string
+	filter     models.ArticleFilter
 }
 
 func NewProcessor(contentDir string) *Processor {
+	cfg := config.GetGlobalConfig()
+	filter, err := FilterFromConfig(cfg.Filter, cfg.Location())
+	if err != nil {
+		color.Yellow("⚠️  筛选条件无效，已忽略: %v", err)
+	}
 	return &Processor{
 		contentDir: contentDir,
+		filter:     filter,
 	}
 }
 
@@ -65,6 +77,70 @@ func (p *Processor) confirmExecution(reader *bufio.Reader, prompt string) bool {
 	return strings.TrimSpace(strings.ToLower(input)) == "y"
 }
 
+// reportSkipped 提示扫描时因 front matter 无法解析而跳过的文件数，详情由内容健康检查列出
+func reportSkipped(count int) {
+	if count > 0 {
+		color.Yellow("⚠️  %d 个文件的 front matter 无法解析，已跳过；可在“内容健康检查”中查看原因", count)
+	}
+}
+
+// selectConflictPolicy 列出上次生成后被手动修改的文件并询问处理方式，直接回车表示跳过；
+// allowMerge 为真时提供三方合并（仅译文支持）
+func (p *Processor) selectConflictPolicy(reader *bufio.Reader, paths []string, allowMerge bool) manifest.Policy {
+	if len(paths) == 0 {
+		return manifest.PolicySkip
+	}
+
+	const maxListed = 20
+	color.Yellow("\n⚠️  以下 %d 个文件在上次生成后被手动修改:", len(paths))
+	for i, path := range paths {
+		if i == maxListed {
+			fmt.Printf("   ... 以及另外 %d 个\n", len(paths)-maxListed)
+			break
+		}
+		fmt.Printf("   • %s\n", path)
+	}
+	fmt.Println("   1. 跳过这些文件，保留手动修改（默认）")
+	fmt.Println("   2. 覆盖手动修改")
+	fmt.Printf("   3. 新结果另存为 %s 文件\n", manifest.NewSuffix)
+	prompt := "请选择 (1-3): "
+	if allowMerge {
+		fmt.Println("   4. 三方合并：只重新翻译源文变化的段落并并入手动修改，冲突处加标记")
+		prompt = "请选择 (1-4): "
+	}
+
+	switch utils.GetChoice(reader, prompt) {
+	case "4":
+		if allowMerge {
+			return manifest.PolicyMerge
+		}
+		return manifest.PolicySkip
+	case "2":
+		color.Red("⚠️  将覆盖 %d 个手动修改的文件", len(paths))
+		return manifest.PolicyOverwrite
+	case "3":
+		return manifest.PolicySaveNew
+	default:
+		return manifest.PolicySkip
+	}
+}
+
+// selectSlugEngine 询问本次生成 slug 的方式，直接回车使用配置的 slug.engine
+func (p *Processor) selectSlugEngine(reader *bufio.Reader) string {
+	engine := config.GetGlobalConfig().Slug.Engine
+	fmt.Println("\n🔤 slug 生成方式:")
+	fmt.Println("   1. 模型翻译为英文")
+	fmt.Println("   2. 拼音（离线，不需要模型服务）")
+	switch utils.GetChoice(reader, fmt.Sprintf("请选择 (1-2，回车使用 %s): ", engine)) {
+	case "1":
+		return config.SlugEngineLLM
+	case "2":
+		return config.SlugEnginePinyin
+	default:
+		return engine
+	}
+}
+
 func (p *Processor) selectPageMode(info string, createCount, updateCount int, reader *bufio.Reader) string {
 	fmt.Println("\n🔧 请选择处理模式:")
 
@@ -128,25 +204,40 @@ func (p *Processor) ProcessAllContent(reader *bufio.Reader) {
 	color.Cyan("=================")
 	fmt.Println("将依次执行以下操作（仅处理新增内容）：")
 	fmt.Println("  1. 生成标签页面")
-	fmt.Println("  2. 生成文章Slug")
-	fmt.Println("  3. 翻译文章为多语言版本")
+	fmt.Println("  2. 生成分类、系列等其他分类法页面")
+	fmt.Println("  3. 生成文章Slug")
+	fmt.Println("  4. 翻译文章为多语言版本（存在过期译文时询问是否重新翻译）")
 	fmt.Println()
 
 	startTime := time.Now()
 	var totalErrors int
 
 	// 步骤1：生成标签页面
-	color.Cyan("\n📖 步骤 1/3: 生成标签页面")
+	color.Cyan("\n📖 步骤 1/4: 生成标签页面")
 	color.Cyan("=======================")
-	if err := p.processTagPagesAutomatically(); err != nil {
+	if taxonomy, ok := config.GetGlobalConfig().Taxonomy("tag"); !ok {
+		color.Yellow("⚠️  站点没有启用 tag 分类法，跳过")
+	} else if err := p.processTermPagesAutomatically(taxonomy); err != nil {
 		color.Red("❌ 标签页面生成失败: %v", err)
 		totalErrors++
 	} else {
 		color.Green("✅ 标签页面生成完成")
 	}
 
-	// 步骤2：生成文章Slug
-	color.Cyan("\n📝 步骤 2/3: 生成文章Slug")
+	// 步骤2：生成其他分类法页面
+	color.Cyan("\n📂 步骤 2/4: 生成其他分类法页面")
+	color.Cyan("=============================")
+	for _, taxonomy := range otherTaxonomies() {
+		if err := p.processTermPagesAutomatically(taxonomy); err != nil {
+			color.Red("❌ %s页面生成失败: %v", taxonomy.Label(), err)
+			totalErrors++
+		} else {
+			color.Green("✅ %s页面生成完成", taxonomy.Label())
+		}
+	}
+
+	// 步骤3：生成文章Slug
+	color.Cyan("\n📝 步骤 3/4: 生成文章Slug")
 	color.Cyan("========================")
 	if err := p.processArticleSlugsAutomatically(); err != nil {
 		color.Red("❌ 文章Slug生成失败: %v", err)
@@ -155,10 +246,10 @@ func (p *Processor) ProcessAllContent(reader *bufio.Reader) {
 		color.Green("✅ 文章Slug生成完成")
 	}
 
-	// 步骤3：翻译文章
-	color.Cyan("\n🌐 步骤 3/3: 翻译文章")
+	// 步骤4：翻译文章
+	color.Cyan("\n🌐 步骤 4/4: 翻译文章")
 	color.Cyan("==================")
-	if err := p.processArticleTranslationAutomatically(); err != nil {
+	if err := p.processArticleTranslationAutomatically(reader); err != nil {
 		color.Red("❌ 文章翻译失败: %v", err)
 		totalErrors++
 	} else {
@@ -177,34 +268,39 @@ func (p *Processor) ProcessAllContent(reader *bufio.Reader) {
 	}
 }
 
-// processTagPagesAutomatically 自动处理标签页面生成
-func (p *Processor) processTagPagesAutomatically() error {
-	pageGenerator := generator.NewTagPageGenerator(p.contentDir)
-	previews, createCount, _ := pageGenerator.PrepareTagPages()
+// processTermPagesAutomatically 自动处理分类法词条页面生成，只新建缺失的页面
+func (p *Processor) processTermPagesAutomatically(taxonomy config.Taxonomy) error {
+	label := taxonomy.Label() + "页面"
+	pageGenerator := generator.NewTermPageGenerator(p.contentDir, taxonomy)
+	pageGenerator.SetFilter(p.filter)
+	previews, createCount, _ := pageGenerator.PreparePages()
+	reportSkipped(pageGenerator.SkippedFiles())
 
 	if createCount == 0 {
-		color.Green("✅ 所有标签页面都是最新的")
+		color.Green("✅ 所有%s都是最新的", label)
 		return nil
 	}
 
-	// 只处理新增的标签页面
+	// 只处理新增的词条页面
 	targetPreviews := filterByMode(previews, "create")
 	if len(targetPreviews) == 0 {
-		color.Green("✅ 没有需要新建的标签页面")
+		color.Green("✅ 没有需要新建的%s", label)
 		return nil
 	}
 
-	color.Cyan("🚀 自动生成新标签页面...")
-	return pageGenerator.GenerateTagPagesWithMode(targetPreviews, "create")
+	color.Cyan("🚀 自动生成新%s...", label)
+	return pageGenerator.GeneratePagesWithMode(targetPreviews, "create")
 }
 
 // processArticleSlugsAutomatically 自动处理文章Slug生成
 func (p *Processor) processArticleSlugsAutomatically() error {
 	slugGenerator := generator.NewArticleSlugGenerator(p.contentDir)
+	slugGenerator.SetFilter(p.filter)
 	previews, createCount, _, err := slugGenerator.PrepareArticleSlugs()
 	if err != nil {
 		return fmt.Errorf("分析文章slug失败: %v", err)
 	}
+	reportSkipped(slugGenerator.SkippedFiles())
 
 	if createCount == 0 {
 		color.Green("✅ 所有文章slug都是最新的")
@@ -222,26 +318,35 @@ func (p *Processor) processArticleSlugsAutomatically() error {
 	return slugGenerator.GenerateArticleSlugsWithMode(targetPreviews, "create")
 }
 
-// processArticleTranslationAutomatically 自动处理文章翻译
-func (p *Processor) processArticleTranslationAutomatically() error {
+// processArticleTranslationAutomatically 自动处理文章翻译：缺失的译文直接翻译，
+// 过期的译文询问后再重新翻译
+func (p *Processor) processArticleTranslationAutomatically(reader *bufio.Reader) error {
 	articleTranslator := generator.NewArticleTranslator(p.contentDir)
-	previews, createCount, _, err := articleTranslator.PrepareArticleTranslations()
+	articleTranslator.SetFilter(p.filter)
+	previews, _, _, err := articleTranslator.PrepareArticleTranslations()
 	if err != nil {
 		return fmt.Errorf("分析文章翻译失败: %v", err)
 	}
+	reportSkipped(articleTranslator.SkippedFiles())
 
-	if createCount == 0 {
-		color.Green("✅ 所有文章都已完全翻译")
-		return nil
+	// 只处理缺失的翻译
+	targetPreviews := filterTranslationsByMode(previews, ModeCreate)
+	mode := ModeCreate
+
+	if staleCount := countTranslationsByStatus(previews, generator.TranslationStale); staleCount > 0 {
+		color.Yellow("⚠️  发现 %d 个源文件修改后过期的译文", staleCount)
+		if p.confirmExecution(reader, "是否同时重新翻译过期译文？(y/n): ") {
+			targetPreviews = append(targetPreviews, filterTranslationsByMode(previews, ModeStale)...)
+			mode = ModeCreate + "+" + ModeStale
+		}
 	}
 
-	// 只处理缺失的翻译
-	targetPreviews := filterTranslationsByMode(previews, "create")
 	if len(targetPreviews) == 0 {
-		color.Green("✅ 没有需要新建的文章翻译")
+		color.Green("✅ 所有文章都已完全翻译")
 		return nil
 	}
+	articleTranslator.SetConflictPolicy(p.selectConflictPolicy(reader, articleTranslator.ModifiedTargets(targetPreviews), true))
 
 	color.Cyan("🚀 自动翻译缺失的文章...")
-	return articleTranslator.TranslateArticlesWithMode(targetPreviews, "create")
+	return articleTranslator.TranslateArticlesWithMode(targetPreviews, mode)
 }
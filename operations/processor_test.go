@@ -1,6 +1,12 @@
 package operations
 
-import "testing"
+import (
+	"hugo-content-suite/config"
+	"hugo-content-suite/generator"
+	"hugo-content-suite/models"
+	"testing"
+	"time"
+)
 
 type testStatus string
 
@@ -12,3 +18,35 @@ func TestFilterByModeExcludesSkippedItems(t *testing.T) {
 		t.Fatalf("all 模式不应包含 skip: %#v", got)
 	}
 }
+
+func TestFilterFromConfigIncludesWholeEndDay(t *testing.T) {
+	filter, err := FilterFromConfig(config.FilterConfig{Drafts: "exclude", DateFrom: "2024-01-01", DateTo: "2024-12-31"}, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastDay := models.Article{Date: time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC)}
+	nextYear := models.Article{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if !filter.Match(lastDay, "") || filter.Match(nextYear, "") {
+		t.Fatal("只写日期的 date_to 应包含当天")
+	}
+	if _, err := FilterFromConfig(config.FilterConfig{Drafts: "maybe"}, time.UTC); err == nil {
+		t.Fatal("无效的 drafts 应报错")
+	}
+}
+
+func TestSlugTargetsIncludeChosenSlugsOutsideMode(t *testing.T) {
+	previews := []generator.ArticleSlugPreview{
+		{FilePath: "a.md", Status: "missing"},
+		{FilePath: "b.md", Status: "update"}, // 原本已是最新，挑选了其他 slug
+		{FilePath: "c.md", Status: "skip"},   // 挑选后保留当前 slug
+		{FilePath: "d.md", Status: "update"},
+	}
+	chosen := map[string]bool{"b.md": true, "c.md": true}
+	var got []string
+	for _, preview := range slugTargets(previews, ModeCreate, chosen) {
+		got = append(got, preview.FilePath)
+	}
+	if len(got) != 2 || got[0] != "a.md" || got[1] != "b.md" {
+		t.Fatalf("targets=%v", got)
+	}
+}
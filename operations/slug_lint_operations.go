@@ -0,0 +1,107 @@
+package operations
+
+import (
+	"fmt"
+	"hugo-content-suite/config"
+	"hugo-content-suite/frontmatter"
+	"hugo-content-suite/generator"
+	"hugo-content-suite/models"
+	"hugo-content-suite/scanner"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// slugLintFinding 是一个不符合 slug 规范的现有 slug
+type slugLintFinding struct {
+	Path       string
+	Label      string // 文章或分类法名称
+	Slug       string
+	Problems   []string
+	Suggestion string // 按规范整理后的 slug
+}
+
+// LintSlugs 检查文章与各分类法词条页面的现有 slug，列出不符合 slug 规范的条目与整理后的建议值。
+// 只报告，不修改文件；需要修改时使用文章 slug 或词条页面生成功能
+func (p *Processor) LintSlugs() {
+	if p.contentDir == "" {
+		color.Red("❌ 内容目录未设置")
+		return
+	}
+
+	color.Cyan("正在检查 slug 规范...")
+	articles, issues, err := scanner.ScanArticlesWithIssues(p.contentDir, false)
+	if err != nil {
+		color.Red("❌ 扫描失败: %v", err)
+		return
+	}
+	reportSkipped(scanner.Skipped(issues))
+	articles = p.filter.Apply(articles, p.contentDir)
+
+	cfg := config.GetGlobalConfig()
+	findings, checked := lintSlugs(cfg, articles, cfg.TaxonomyList())
+	if len(findings) == 0 {
+		color.Green("✅ 共检查 %d 个 slug，全部符合规范", checked)
+		return
+	}
+
+	color.Cyan("\n📋 slug 规范报告")
+	for _, finding := range findings {
+		path := finding.Path
+		if rel, err := filepath.Rel(p.contentDir, finding.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+		color.Yellow("  ⚠️  [%s] %s", finding.Label, path)
+		fmt.Printf("     %s：%s\n", finding.Slug, strings.Join(finding.Problems, "；"))
+		if finding.Suggestion != finding.Slug {
+			fmt.Printf("     建议: %s\n", finding.Suggestion)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("共检查 %d 个 slug，%d 个不符合规范\n", checked, len(findings))
+	if cfg.Slug.LockPublished() {
+		fmt.Println("已发布文章的 slug 受 slug.lock 保护，修改前请确认旧地址会写入 aliases")
+	}
+}
+
+// lintSlugs 返回文章与词条页面中不符合规范的 slug 以及检查的总数
+func lintSlugs(cfg *config.Config, articles []models.Article, taxonomies []config.Taxonomy) ([]slugLintFinding, int) {
+	var findings []slugLintFinding
+	checked := 0
+	// text 是生成 slug 的标题或词条名，用于判断 slug 是拼音还是英文，两者的虚词表不同
+	check := func(path, label, slug, text string) {
+		if slug == "" {
+			return
+		}
+		checked++
+		lang := generator.SlugLanguage(slug, text)
+		if problems := cfg.Slug.Violations(slug, lang); len(problems) > 0 {
+			findings = append(findings, slugLintFinding{
+				Path: path, Label: label, Slug: slug, Problems: problems,
+				Suggestion: cfg.Slug.Apply(slug, lang),
+			})
+		}
+	}
+
+	for _, article := range articles {
+		check(article.FilePath, "文章", article.Slug, article.Title)
+	}
+	for _, taxonomy := range taxonomies {
+		if taxonomy.Dir == "" {
+			continue
+		}
+		pages, _ := filepath.Glob(filepath.Join(taxonomy.Dir, "*", "_index.md"))
+		for _, page := range pages {
+			doc, err := frontmatter.ReadFile(page)
+			if err != nil {
+				continue
+			}
+			slug, _, _ := doc.GetString("slug")
+			title, _, _ := doc.GetString("title")
+			check(page, taxonomy.Label(), slug, title)
+		}
+	}
+	return findings, checked
+}
@@ -0,0 +1,51 @@
+package operations
+
+import (
+	"hugo-content-suite/config"
+	"hugo-content-suite/models"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLintSlugsReportsArticlesAndTermPages(t *testing.T) {
+	tagsDir := t.TempDir()
+	for name, slug := range map[string]string{"go": "golang", "long": "a-guide-to-the-go-scheduler"} {
+		if err := os.MkdirAll(filepath.Join(tagsDir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tagsDir, name, "_index.md"), []byte("---\nslug: \""+slug+"\"\n---\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{Slug: config.SlugConfig{MaxWords: 4, MaxLength: 40}}
+	articles := []models.Article{
+		{FilePath: "ok.md", Slug: "go-scheduler"},
+		{FilePath: "long.md", Slug: "how-we-moved-our-monolith-onto-kubernetes"},
+		{FilePath: "draft.md"},
+	}
+	taxonomies := []config.Taxonomy{{Singular: "tag", Plural: "tags", TaxonomySettings: config.TaxonomySettings{Dir: tagsDir}}}
+
+	findings, checked := lintSlugs(cfg, articles, taxonomies)
+	if checked != 4 || len(findings) != 2 {
+		t.Fatalf("checked=%d findings=%+v", checked, findings)
+	}
+	for _, finding := range findings {
+		if finding.Suggestion == finding.Slug || len(cfg.Slug.Violations(finding.Suggestion, "en")) > 0 {
+			t.Errorf("%s 的建议值 %q 应符合规范", finding.Path, finding.Suggestion)
+		}
+	}
+}
+
+func TestLintSlugsKeepsPinyinSyllablesThatLookLikeStopwords(t *testing.T) {
+	cfg := &config.Config{Slug: config.SlugConfig{MaxWords: 8, MaxLength: 60}}
+	articles := []models.Article{
+		{FilePath: "pinyin.md", Title: "安全策略", Slug: "an-quan-ce-lve"},
+		{FilePath: "mixed.md", Title: "Go 安全", Slug: "go-an-quan"},
+		{FilePath: "english.md", Title: "安全策略", Slug: "an-security-policy"},
+	}
+	findings, checked := lintSlugs(cfg, articles, nil)
+	if checked != 3 || len(findings) != 1 || findings[0].Path != "english.md" || findings[0].Suggestion != "security-policy" {
+		t.Fatalf("checked=%d findings=%+v", checked, findings)
+	}
+}
@@ -0,0 +1,127 @@
+// Package pinyin 把汉字转写为不带声调的拼音，用于在没有模型服务时生成 slug。
+// 读音表随程序打包：chars.txt 是每个汉字的默认读音，phrases.txt 是多音字在常用词语中的读音，
+// 转写时按最长匹配优先使用词语读音。
+package pinyin
+
+import (
+	"bufio"
+	_ "embed"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:embed chars.txt
+var charsData string
+
+//go:embed phrases.txt
+var phrasesData string
+
+var (
+	loadOnce  sync.Once
+	chars     map[rune]string
+	syllables map[string]bool
+	phrases   map[string][]string
+	maxPhrase int
+)
+
+func load() {
+	chars = make(map[rune]string, 27000)
+	syllables = make(map[string]bool, 420)
+	eachLine(charsData, func(fields []string) {
+		syllables[fields[0]] = true
+		for _, r := range fields[1] {
+			chars[r] = fields[0]
+		}
+	})
+	phrases = make(map[string][]string)
+	eachLine(phrasesData, func(fields []string) {
+		word := []rune(fields[0])
+		if len(word) != len(fields)-1 {
+			return
+		}
+		if len(word) == 1 {
+			chars[word[0]] = fields[1]
+			return
+		}
+		phrases[fields[0]] = fields[1:]
+		if len(word) > maxPhrase {
+			maxPhrase = len(word)
+		}
+	})
+}
+
+func eachLine(data string, fn func(fields []string)) {
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) >= 2 {
+			fn(fields)
+		}
+	}
+}
+
+// Words 把文本转写为单词列表：每个汉字为一个拼音音节，连续的字母与数字保持为一个小写单词，
+// 其余字符作为分隔。读音表中没有的汉字被忽略。
+func Words(text string) []string {
+	loadOnce.Do(load)
+
+	var words []string
+	runes := []rune(text)
+	var latin strings.Builder
+	flush := func() {
+		if latin.Len() > 0 {
+			words = append(words, latin.String())
+			latin.Reset()
+		}
+	}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if !unicode.Is(unicode.Han, r) {
+			if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				latin.WriteRune(unicode.ToLower(r))
+			} else {
+				flush()
+			}
+			i++
+			continue
+		}
+		flush()
+		if syllables, n := matchPhrase(runes[i:]); n > 0 {
+			words = append(words, syllables...)
+			i += n
+			continue
+		}
+		if syllable, ok := chars[r]; ok {
+			words = append(words, syllable)
+		}
+		i++
+	}
+	flush()
+	return words
+}
+
+// matchPhrase 返回 runes 开头最长的词语读音及其长度，没有匹配时长度为 0
+func matchPhrase(runes []rune) ([]string, int) {
+	for n := min(maxPhrase, len(runes)); n >= 2; n-- {
+		if syllables, ok := phrases[string(runes[:n])]; ok {
+			return syllables, n
+		}
+	}
+	return nil, 0
+}
+
+// IsSyllable 报告 word 是否为读音表中的拼音音节（不带声调，ü 写作 v）
+func IsSyllable(word string) bool {
+	loadOnce.Do(load)
+	return syllables[word]
+}
+
+// Slug 返回文本的拼音 slug，单词以连字符连接，如“Go 语言调度器” → go-yu-yan-diao-du-qi
+func Slug(text string) string {
+	return strings.Join(Words(text), "-")
+}
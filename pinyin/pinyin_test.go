@@ -0,0 +1,18 @@
+package pinyin
+
+import "testing"
+
+func TestSlugUsesPhraseReadingsForHeteronyms(t *testing.T) {
+	for text, want := range map[string]string{
+		"Go 语言调度器":      "go-yu-yan-diao-du-qi",
+		"重庆银行的成长":       "chong-qing-yin-hang-de-cheng-zhang",
+		"长期重要的音乐":       "chang-qi-zhong-yao-de-yin-yue",
+		"K8s 入门：快乐学习":   "k8s-ru-men-kuai-le-xue-xi",
+		"绿色女装":          "lv-se-nv-zhuang",
+		"调试与调用，v2.0 更新": "tiao-shi-yu-diao-yong-v2-0-geng-xin",
+	} {
+		if got := Slug(text); got != want {
+			t.Errorf("Slug(%q)=%q want %q", text, got, want)
+		}
+	}
+}
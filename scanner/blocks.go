@@ -0,0 +1,328 @@
+package scanner
+
+import (
+	"hugo-content-suite/models"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// markdownParser 与 Hugo 默认启用的 Goldmark 扩展保持一致，表格、脚注等才能被识别为整体。
+var markdownParser = goldmark.New(goldmark.WithExtensions(
+	extension.Table,
+	extension.Strikethrough,
+	extension.Footnote,
+	extension.DefinitionList,
+)).Parser()
+
+// verbatimShortcodes 中的成对短代码内容是代码或公式，原样保留。
+var verbatimShortcodes = map[string]bool{
+	"highlight": true,
+	"code":      true,
+	"math":      true,
+	"katex":     true,
+	"mermaid":   true,
+	"chart":     true,
+	"raw":       true,
+	"html":      true,
+}
+
+var (
+	shortcodeOpenRe  = regexp.MustCompile(`^\{\{[<%]\s*([A-Za-z0-9_.\-]+)`)
+	tableDelimiterRe = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	setextUnderRe    = regexp.MustCompile(`^\s{0,3}(=+|-+)\s*$`)
+)
+
+// lineRange 是按行号表示的块范围，end 含在内。
+type lineRange struct {
+	start, end int
+	kind       models.BlockKind
+	typ        string
+}
+
+// SegmentBody 按 Markdown 语法把正文切分为块并分类。
+// Goldmark 负责识别 CommonMark 与 GFM 结构；$$ 公式块和跨空行的成对短代码不属于
+// Markdown 语法，先按行识别后整体保留。解析器未覆盖的非空行（如链接引用定义）原样保留。
+func SegmentBody(body string) []models.Block {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+
+	var ranges []lineRange
+	chunkStart := 0
+	for i := 0; i < len(lines); {
+		// 围栏代码交给 Goldmark，这里只跳过其中可能出现的 $$ 与短代码。
+		if isFenceLine(lines[i]) {
+			i = fenceEnd(lines, i) + 1
+			continue
+		}
+		r, ok := protectedRange(lines, i)
+		if !ok {
+			i++
+			continue
+		}
+		ranges = append(ranges, markdownRanges(lines, chunkStart, r.start)...)
+		ranges = append(ranges, r)
+		i = r.end + 1
+		chunkStart = i
+	}
+	ranges = append(ranges, markdownRanges(lines, chunkStart, len(lines))...)
+
+	return buildBlocks(lines, ranges)
+}
+
+// protectedRange 判断第 i 行是否开始一个 $$ 公式块或成对短代码。
+func protectedRange(lines []string, i int) (lineRange, bool) {
+	trimmed := strings.TrimSpace(lines[i])
+
+	if strings.HasPrefix(trimmed, "$$") {
+		if len(trimmed) >= 4 && strings.HasSuffix(trimmed, "$$") {
+			// 单行公式只有独占一段时才是公式块，否则属于所在段落。
+			if standalone(lines, i) {
+				return lineRange{start: i, end: i, kind: models.BlockVerbatim, typ: "math"}, true
+			}
+			return lineRange{}, false
+		}
+		for j := i + 1; j < len(lines); j++ {
+			if strings.HasSuffix(strings.TrimSpace(lines[j]), "$$") {
+				return lineRange{start: i, end: j, kind: models.BlockVerbatim, typ: "math"}, true
+			}
+		}
+		return lineRange{}, false
+	}
+
+	match := shortcodeOpenRe.FindStringSubmatch(trimmed)
+	if match == nil || strings.HasSuffix(trimmed, "/>}}") || strings.HasSuffix(trimmed, "/%}}") {
+		return lineRange{}, false
+	}
+	name := match[1]
+	closeRe := regexp.MustCompile(`\{\{[<%]\s*/\s*` + regexp.QuoteMeta(name) + `\s*[>%]\}\}`)
+	if closeRe.MatchString(lines[i]) {
+		// 同一行内闭合的短代码是行内内容，随所在块处理。
+		return lineRange{}, false
+	}
+	for j := i + 1; j < len(lines); j++ {
+		if closeRe.MatchString(lines[j]) {
+			kind := models.BlockTranslatable
+			if verbatimShortcodes[strings.ToLower(name)] {
+				kind = models.BlockVerbatim
+			}
+			return lineRange{start: i, end: j, kind: kind, typ: "shortcode"}, true
+		}
+	}
+	return lineRange{}, false
+}
+
+// markdownRanges 用 Goldmark 解析 lines[from:to]，返回顶层块的行范围。
+func markdownRanges(lines []string, from, to int) []lineRange {
+	if from >= to {
+		return nil
+	}
+	chunk := lines[from:to]
+	source := []byte(strings.Join(chunk, "\n"))
+	lineStarts := make([]int, len(chunk))
+	offset := 0
+	for i, line := range chunk {
+		lineStarts[i] = offset
+		offset += len(line) + 1
+	}
+	lineOf := func(pos int) int {
+		return sort.Search(len(lineStarts), func(i int) bool { return lineStarts[i] > pos }) - 1
+	}
+
+	doc := markdownParser.Parse(text.NewReader(source))
+
+	var ranges []lineRange
+	var add func(node ast.Node)
+	add = func(node ast.Node) {
+		if _, ok := node.(*extast.FootnoteList); ok {
+			for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+				add(child)
+			}
+			return
+		}
+		start, end, ok := nodeLines(node, chunk, lineOf)
+		if !ok {
+			return
+		}
+		kind, typ := classifyNode(node)
+		ranges = append(ranges, lineRange{start: from + start, end: from + end, kind: kind, typ: typ})
+	}
+	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
+		add(node)
+	}
+	return ranges
+}
+
+// nodeLines 汇总节点及其后代覆盖的行。Goldmark 不记录围栏行、Setext 标题下划线和
+// 只有表头的表格分隔行，这里按相邻行补齐，避免这些行与内容被拆到不同块中。
+func nodeLines(node ast.Node, lines []string, lineOf func(int) int) (int, int, bool) {
+	start, end := -1, -1
+	cover := func(line int) {
+		if line < 0 || line >= len(lines) {
+			return
+		}
+		if start < 0 || line < start {
+			start = line
+		}
+		if line > end {
+			end = line
+		}
+	}
+
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || n.Type() != ast.TypeBlock {
+			return ast.WalkContinue, nil
+		}
+		segments := n.Lines()
+		first, last := -1, -1
+		for i := 0; i < segments.Len(); i++ {
+			segment := segments.At(i)
+			line := lineOf(segment.Start)
+			if first < 0 {
+				first = line
+			}
+			last = lineOf(max(segment.Start, segment.Stop-1))
+			cover(line)
+			cover(last)
+		}
+
+		switch block := n.(type) {
+		case *ast.FencedCodeBlock:
+			if block.Info != nil {
+				first = lineOf(block.Info.Segment.Start)
+				cover(first)
+				if last < 0 {
+					last = first
+				}
+			} else if first >= 0 {
+				first--
+				cover(first)
+			}
+			if last >= 0 && last+1 < len(lines) && isFenceLine(lines[last+1]) {
+				cover(last + 1)
+			}
+		case *ast.HTMLBlock:
+			if block.HasClosure() {
+				cover(lineOf(block.ClosureLine.Start))
+			}
+		case *ast.Heading:
+			if first >= 0 && !strings.HasPrefix(strings.TrimSpace(lines[first]), "#") &&
+				last+1 < len(lines) && setextUnderRe.MatchString(lines[last+1]) {
+				cover(last + 1)
+			}
+		case *extast.Table:
+			_, headerOnly := block.LastChild().(*extast.TableHeader)
+			if headerOnly && end >= 0 && end+1 < len(lines) && tableDelimiterRe.MatchString(lines[end+1]) {
+				cover(end + 1)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return start, end, start >= 0
+}
+
+func standalone(lines []string, i int) bool {
+	return (i == 0 || strings.TrimSpace(lines[i-1]) == "") && (i+1 >= len(lines) || strings.TrimSpace(lines[i+1]) == "")
+}
+
+func isFenceLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// fenceEnd 返回从第 i 行开始的围栏代码的结束行，未闭合时到正文末尾。
+func fenceEnd(lines []string, i int) int {
+	fence := strings.TrimSpace(lines[i])[:3]
+	for j := i + 1; j < len(lines); j++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[j]), fence) {
+			return j
+		}
+	}
+	return len(lines) - 1
+}
+
+// classifyNode 决定顶层块的翻译方式。
+func classifyNode(node ast.Node) (models.BlockKind, string) {
+	switch node.(type) {
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		return models.BlockVerbatim, "code"
+	case *ast.HTMLBlock:
+		return models.BlockVerbatim, "html"
+	case *ast.ThematicBreak:
+		return models.BlockVerbatim, "thematic_break"
+	case *extast.Table:
+		return models.BlockPartial, "table"
+	case *extast.Footnote:
+		return models.BlockTranslatable, "footnote"
+	case *ast.Heading:
+		return models.BlockTranslatable, "heading"
+	case *ast.List:
+		return models.BlockTranslatable, "list"
+	case *ast.Blockquote:
+		return models.BlockTranslatable, "blockquote"
+	case *extast.DefinitionList:
+		return models.BlockTranslatable, "definition_list"
+	default:
+		return models.BlockTranslatable, "paragraph"
+	}
+}
+
+// buildBlocks 按行号排序并合并重叠范围，未被任何块覆盖的连续非空行作为原样块保留。
+func buildBlocks(lines []string, ranges []lineRange) []models.Block {
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].start < ranges[j].start })
+
+	var merged []lineRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.start <= merged[n-1].end {
+			last := &merged[n-1]
+			last.end = max(last.end, r.end)
+			if r.kind == models.BlockVerbatim || last.kind != r.kind {
+				last.kind = models.BlockVerbatim
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	var blocks []models.Block
+	appendBlock := func(start, end int, kind models.BlockKind, typ string) {
+		content := strings.TrimRight(strings.Join(lines[start:end+1], "\n"), " \t\n")
+		content = strings.TrimLeft(content, "\n")
+		if strings.TrimSpace(content) == "" {
+			return
+		}
+		blocks = append(blocks, models.Block{Kind: kind, Type: typ, Text: content})
+	}
+	flushGap := func(from, to int) {
+		gapStart := -1
+		for i := from; i < to; i++ {
+			if strings.TrimSpace(lines[i]) == "" {
+				if gapStart >= 0 {
+					appendBlock(gapStart, i-1, models.BlockVerbatim, "raw")
+					gapStart = -1
+				}
+				continue
+			}
+			if gapStart < 0 {
+				gapStart = i
+			}
+		}
+		if gapStart >= 0 {
+			appendBlock(gapStart, to-1, models.BlockVerbatim, "raw")
+		}
+	}
+
+	next := 0
+	for _, r := range merged {
+		flushGap(next, r.start)
+		appendBlock(r.start, r.end, r.kind, r.typ)
+		next = r.end + 1
+	}
+	flushGap(next, len(lines))
+	return blocks
+}
@@ -0,0 +1,49 @@
+package scanner
+
+import (
+	"hugo-content-suite/models"
+	"testing"
+)
+
+func TestSegmentBodyKeepsStructuresIntact(t *testing.T) {
+	body := "# 标题\n\n" +
+		"| 名称 | 说明 |\n|---|---|\n| a | 苹果 |\n\n" +
+		"    indented code\n\n" +
+		"- 列表\n  - 嵌套\n\n  继续\n\n" +
+		"```go\nx\n\n$$\n```\n\n" +
+		"$$\na\n\nb\n$$\n\n" +
+		"{{< notice info >}}\n提示\n\n第二段\n{{< /notice >}}\n\n" +
+		"{{< highlight go >}}\nfmt.Println(1)\n\nfmt.Println(2)\n{{< /highlight >}}\n\n" +
+		"正文[^1]\n\n[^1]: 脚注\n    第二行\n\n" +
+		"Setext\n===\n"
+
+	want := []models.Block{
+		{Kind: models.BlockTranslatable, Type: "heading", Text: "# 标题"},
+		{Kind: models.BlockPartial, Type: "table", Text: "| 名称 | 说明 |\n|---|---|\n| a | 苹果 |"},
+		{Kind: models.BlockVerbatim, Type: "code", Text: "    indented code"},
+		{Kind: models.BlockTranslatable, Type: "list", Text: "- 列表\n  - 嵌套\n\n  继续"},
+		{Kind: models.BlockVerbatim, Type: "code", Text: "```go\nx\n\n$$\n```"},
+		{Kind: models.BlockVerbatim, Type: "math", Text: "$$\na\n\nb\n$$"},
+		{Kind: models.BlockTranslatable, Type: "shortcode", Text: "{{< notice info >}}\n提示\n\n第二段\n{{< /notice >}}"},
+		{Kind: models.BlockVerbatim, Type: "shortcode", Text: "{{< highlight go >}}\nfmt.Println(1)\n\nfmt.Println(2)\n{{< /highlight >}}"},
+		{Kind: models.BlockTranslatable, Type: "paragraph", Text: "正文[^1]"},
+		{Kind: models.BlockTranslatable, Type: "footnote", Text: "[^1]: 脚注\n    第二行"},
+		{Kind: models.BlockTranslatable, Type: "heading", Text: "Setext\n==="},
+	}
+	got := SegmentBody(body)
+	if len(got) != len(want) {
+		t.Fatalf("块数量为 %d，应为 %d: %#v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("第 %d 块为 %#v，应为 %#v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSegmentBodyKeepsUncoveredLines(t *testing.T) {
+	got := SegmentBody("见[文档][doc]。\n\n[doc]: https://example.com\n")
+	if len(got) != 2 || got[1].Kind != models.BlockVerbatim || got[1].Text != "[doc]: https://example.com" {
+		t.Fatalf("链接引用定义应原样保留: %#v", got)
+	}
+}
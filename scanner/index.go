@@ -0,0 +1,149 @@
+package scanner
+
+import (
+	"encoding/json"
+	"hugo-content-suite/config"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// indexFileName 是扫描索引在 runtime_dir 中的文件名。
+const indexFileName = "scan_index.json"
+
+// indexVersion 在索引内容的含义变化时递增，旧索引整体失效。
+const indexVersion = 3
+
+// indexEntry 缓存单个文件的解析结果，文件的修改时间与大小不变时直接复用。
+// 只缓存元数据，正文由 LoadContent 按需读取。
+type indexEntry struct {
+	ModTime  int64    `json:"mod_time"`
+	Size     int64    `json:"size"`
+	Article  *Article `json:"article,omitempty"`
+	Problems []string `json:"problems,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+type scanIndex struct {
+	Version  int                    `json:"version"`
+	TimeZone string                 `json:"time_zone"` // 日期按时区解析，时区变化时索引失效
+	Terms    string                 `json:"terms"`     // 解析的其他分类法键，分类法变化时索引失效
+	Fields   string                 `json:"fields"`    // 前置数据字段策略，决定源文件哈希的范围，变化时索引失效
+	Entries  map[string]*indexEntry `json:"entries"`
+
+	path  string
+	dirty bool
+}
+
+var (
+	indexMu     sync.Mutex
+	cachedIndex *scanIndex
+)
+
+// indexPath 返回索引文件路径。runtime_dir 未解析为绝对路径（例如未加载配置文件时的默认配置）
+// 时不落盘，只在进程内缓存，避免在任意工作目录写出运行文件。
+func indexPath(cfg *config.Config) string {
+	if !filepath.IsAbs(cfg.Paths.RuntimeDir) {
+		return ""
+	}
+	return filepath.Join(cfg.Paths.RuntimeDir, indexFileName)
+}
+
+// acquireIndex 返回当前配置对应的索引；调用方持有 indexMu 期间独占使用。
+func acquireIndex(cfg *config.Config) *scanIndex {
+	path := indexPath(cfg)
+	terms := strings.Join(termTaxonomyKeys(cfg), ",")
+	fields := fieldFingerprint(cfg)
+	if cachedIndex != nil && cachedIndex.path == path && cachedIndex.TimeZone == cfg.TimeZone && cachedIndex.Terms == terms && cachedIndex.Fields == fields {
+		return cachedIndex
+	}
+
+	index := &scanIndex{Version: indexVersion, TimeZone: cfg.TimeZone, Terms: terms, Fields: fields, Entries: make(map[string]*indexEntry), path: path}
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			var stored scanIndex
+			// 索引只是缓存，损坏或版本不符时直接重建。
+			if json.Unmarshal(data, &stored) == nil && stored.Version == indexVersion && stored.TimeZone == cfg.TimeZone && stored.Terms == terms && stored.Fields == fields && stored.Entries != nil {
+				index.Entries = stored.Entries
+			}
+		}
+	}
+	cachedIndex = index
+	return index
+}
+
+// fieldFingerprint 返回可翻译字段与同步策略的摘要，索引中缓存的源文件哈希依赖它们
+func fieldFingerprint(cfg *config.Config) string {
+	data, _ := json.Marshal(struct {
+		Fields   []config.TranslatableField
+		Policies map[string]string
+		Default  string
+	}{cfg.FrontMatter.Fields(), cfg.FrontMatter.FieldPolicies, cfg.FrontMatter.DefaultPolicy})
+	return string(data)
+}
+
+// termTaxonomyKeys 返回 tags、categories 以外需要解析词条的前置数据键，按名称排序。
+func termTaxonomyKeys(cfg *config.Config) []string {
+	var keys []string
+	for _, taxonomy := range cfg.TaxonomyList() {
+		key := strings.ToLower(taxonomy.Plural)
+		if key != "tags" && key != "categories" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (idx *scanIndex) lookup(path string, info os.FileInfo) (*indexEntry, bool) {
+	entry, ok := idx.Entries[path]
+	if !ok || entry.ModTime != info.ModTime().UnixNano() || entry.Size != info.Size() {
+		return nil, false
+	}
+	return entry, true
+}
+
+func (idx *scanIndex) store(path string, entry *indexEntry) {
+	idx.Entries[path] = entry
+	idx.dirty = true
+}
+
+// prune 删除扫描目录内已不存在的文件记录，其他目录的记录保持不变。
+func (idx *scanIndex) prune(roots []string, seen map[string]bool) {
+	for path := range idx.Entries {
+		if seen[path] {
+			continue
+		}
+		for _, root := range roots {
+			if rel, err := filepath.Rel(root, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				delete(idx.Entries, path)
+				idx.dirty = true
+				break
+			}
+		}
+	}
+}
+
+func (idx *scanIndex) save() error {
+	if !idx.dirty || idx.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	tmp := idx.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, idx.path); err != nil {
+		return err
+	}
+	idx.dirty = false
+	return nil
+}
@@ -1,253 +1,501 @@
 package scanner
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"hugo-content-suite/config"
+	"hugo-content-suite/frontmatter"
+	"hugo-content-suite/layout"
 	"hugo-content-suite/models"
-	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
-
-	"gopkg.in/yaml.v3"
+	"sync"
+	"time"
 )
 
 // Article 类型别名，方便引用
 type Article = models.Article
 
-// 默认只扫描 index.md，不读取内容详情
+// FileIssue 记录扫描时发现的问题文件。Skipped 为真表示 front matter 无法解析，
+// 文件未出现在扫描结果中；否则文章已按可识别的字段收录，Reason 说明被忽略的字段。
+type FileIssue struct {
+	Path    string
+	Lang    string
+	Reason  string
+	Skipped bool
+}
+
+// 默认只扫描源文件，不读取内容详情；问题文件不报告，需要时使用 ScanArticlesWithIssues
 func ScanArticles(dir string) ([]Article, error) {
-	return scanArticlesInternal(dir, false, false)
+	articles, _, err := scanArticlesInternal(dir, false, false)
+	return articles, err
 }
 
-// 支持 allLangs 参数，不读取内容详情
+// 支持 allLangs 参数，同时扫描各语言译文，不读取内容详情
 func ScanArticlesWithLangs(dir string, allLangs bool) ([]Article, error) {
-	return scanArticlesInternal(dir, allLangs, false)
+	articles, _, err := scanArticlesInternal(dir, allLangs, false)
+	return articles, err
 }
 
 // 用于翻译模块：扫描并读取完整内容信息
 func ScanArticlesForTranslation(dir string) ([]Article, error) {
-	return scanArticlesInternal(dir, false, true)
+	articles, _, err := scanArticlesInternal(dir, false, true)
+	return articles, err
 }
 
-// 内部统一扫描函数
-func scanArticlesInternal(dir string, allLangs bool, withContent bool) ([]Article, error) {
-	var articles []Article
+// ScanArticlesWithIssues 扫描文章并返回全部问题文件，单个文件出错不会中断扫描。
+func ScanArticlesWithIssues(dir string, allLangs bool) ([]Article, []FileIssue, error) {
+	return scanArticlesInternal(dir, allLangs, false)
+}
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// Skipped 返回 issues 中未收录到扫描结果的文件数
+func Skipped(issues []FileIssue) int {
+	skipped := 0
+	for _, issue := range issues {
+		if issue.Skipped {
+			skipped++
 		}
+	}
+	return skipped
+}
+
+// candidate 是遍历阶段找到的内容文件，解析在并发阶段完成。
+type candidate struct {
+	path  string
+	key   string // 索引键：绝对路径
+	lang  string
+	info  os.FileInfo
+	entry *indexEntry
+}
 
-		base := filepath.Base(path)
-		if allLangs {
-			// 匹配 index.md, index.en.md, index.zh-cn.md 等
-			if !strings.HasPrefix(base, "index.") || !strings.HasSuffix(base, ".md") {
+// 内部统一扫描函数。源文件与译文的识别交给内容布局：文件名后缀布局下
+// index.md、_index.md 与 foo.md 都是源文件；按语言分目录的布局下还会遍历各语言目录。
+// 遍历只读取目录信息，修改时间与大小未变的文件直接使用 runtime_dir 中的索引，
+// 其余文件并发解析；withContent 为真时再并发读取正文。
+func scanArticlesInternal(dir string, allLangs bool, withContent bool) ([]Article, []FileIssue, error) {
+	cfg := config.GetGlobalConfig()
+	loc := cfg.Location()
+	termKeys := termTaxonomyKeys(cfg)
+	contentLayout := layout.ForContentDir(dir)
+
+	roots := []string{dir}
+	if allLangs {
+		roots = contentLayout.Roots(dir)
+	}
+
+	var candidates []*candidate
+	var walkIssues []FileIssue
+	var walkedRoots []string
+	bundles := make(map[string]bool)
+	for i, root := range roots {
+		if i > 0 {
+			if _, err := os.Stat(root); os.IsNotExist(err) {
+				continue
+			}
+		}
+		if absRoot, err := filepath.Abs(root); err == nil {
+			walkedRoots = append(walkedRoots, absRoot)
+		}
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			// 无法读取的目录或文件记为问题文件，跳过后继续扫描；内容根目录本身不可读时才中断
+			if err != nil {
+				if path == root {
+					return err
+				}
+				lang, _ := contentLayout.Language(path)
+				walkIssues = append(walkIssues, FileIssue{Path: path, Lang: lang, Reason: err.Error(), Skipped: true})
+				if info != nil && info.IsDir() {
+					return filepath.SkipDir
+				}
 				return nil
 			}
-		} else {
-			// 只扫描 index.md
-			if base != "index.md" {
+			if info.IsDir() {
+				return nil
+			}
+
+			lang, ok := contentLayout.Language(path)
+			if !ok || (!allLangs && lang != "") {
+				return nil
+			}
+			if isBundleResource(root, path, bundles) {
 				return nil
 			}
+
+			key, err := filepath.Abs(path)
+			if err != nil {
+				key = path
+			}
+			candidates = append(candidates, &candidate{path: path, key: key, lang: lang, info: info})
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
 		}
+	}
+
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	index := acquireIndex(cfg)
 
-		article, err := parseMarkdownFile(path, withContent)
+	var pending []*candidate
+	seen := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		seen[c.key] = true
+		if entry, ok := index.lookup(c.key, c.info); ok {
+			c.entry = entry
+		} else {
+			pending = append(pending, c)
+		}
+	}
+	parallel(len(pending), func(i int) {
+		c := pending[i]
+		entry := &indexEntry{ModTime: c.info.ModTime().UnixNano(), Size: c.info.Size()}
+		article, problems, err := parseMarkdownFile(c.path, loc, termKeys)
 		if err != nil {
-			return fmt.Errorf("解析文章 %s: %w", path, err)
+			entry.Error = err.Error()
+		} else {
+			entry.Article = article
+			entry.Problems = problems
 		}
+		c.entry = entry
+	})
+	for _, c := range pending {
+		index.store(c.key, c.entry)
+	}
+	index.prune(walkedRoots, seen)
+	if err := index.save(); err != nil {
+		fmt.Printf("⚠️  保存扫描索引失败: %v\n", err)
+	}
 
-		if article != nil {
-			articles = append(articles, *article)
+	var articles []Article
+	issues := walkIssues
+	for _, c := range candidates {
+		if c.entry.Error != "" {
+			issues = append(issues, FileIssue{Path: c.path, Lang: c.lang, Reason: c.entry.Error, Skipped: true})
+			continue
 		}
+		for _, problem := range c.entry.Problems {
+			issues = append(issues, FileIssue{Path: c.path, Lang: c.lang, Reason: problem})
+		}
+		article := *c.entry.Article
+		article.FilePath = c.path
+		article.Lang = c.lang
+		articles = append(articles, article)
+	}
 
-		return nil
-	})
+	if withContent {
+		errs := make([]error, len(articles))
+		parallel(len(articles), func(i int) {
+			errs[i] = LoadContent(&articles[i])
+		})
+		for _, err := range errs {
+			if err != nil {
+				return articles, issues, err
+			}
+		}
+	}
 
-	return articles, err
+	return articles, issues, nil
 }
 
-func parseMarkdownFile(filePath string, withContent bool) (*Article, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+// parallel 以 CPU 数量的并发度执行 fn(0..n-1)。
+func parallel(n int, fn func(i int)) {
+	workers := runtime.NumCPU()
+	if workers > n {
+		workers = n
+	}
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
 	}
-	defer file.Close()
+	close(next)
+	wg.Wait()
+}
 
-	// 读取整个文件内容
-	content, err := io.ReadAll(file)
+// LoadContent 按需读取文章的原始前置数据与正文段落，扫描结果默认只含元数据。
+func LoadContent(article *Article) error {
+	doc, err := frontmatter.ReadFile(article.FilePath)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("读取文章 %s: %w", article.FilePath, err)
+	}
+	article.FrontMatter = doc.FrontMatter
+	article.FrontMatterFormat = string(doc.Format)
+	article.LineEnding = doc.LineEnding
+	article.BOM = doc.BOM
+	article.SourceHash = SourceHash(doc)
+
+	// 按 Markdown 语法切分正文，BodyContent 保留各块文本
+	article.BodyBlocks = SegmentBody(doc.Body)
+	article.BodyContent = make([]string, len(article.BodyBlocks))
+	for i, block := range article.BodyBlocks {
+		article.BodyContent[i] = block.Text
 	}
 
-	contentStr := string(content)
+	// 计算正文字符数
+	article.CharCount = len([]rune(doc.Body))
+	return nil
+}
 
-	// 查找前置数据边界
-	lines := strings.Split(contentStr, "\n")
-	var frontMatterStart, frontMatterEnd int = -1, -1
+// HasContent 判断文章是否已读取正文。
+func HasContent(article *Article) bool {
+	return article.FrontMatterFormat != "" || article.BodyContent != nil
+}
 
-	for i, line := range lines {
-		if strings.TrimSpace(line) == "---" {
-			if frontMatterStart == -1 {
-				frontMatterStart = i
-			} else {
-				frontMatterEnd = i
-				break
-			}
+// isBundleResource 判断 Markdown 文件是否为叶子包内的资源：叶子包目录含 index.md，
+// 其中（含子目录）除 index.* 之外的 Markdown 文件不是独立页面。只检查 root 以内的目录。
+func isBundleResource(root, path string, bundles map[string]bool) bool {
+	base := filepath.Base(path)
+	if strings.HasPrefix(base, "index.") || strings.HasPrefix(base, "_index.") {
+		return false
+	}
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		isBundle, cached := bundles[dir]
+		if !cached {
+			matches, _ := filepath.Glob(filepath.Join(dir, "index.*md"))
+			isBundle = len(matches) > 0
+			bundles[dir] = isBundle
+		}
+		if isBundle {
+			return true
 		}
+		if dir == filepath.Clean(root) || filepath.Dir(dir) == dir {
+			return false
+		}
+	}
+}
+
+// parseMarkdownFile 宽松解析前置数据：分类法接受单个值或列表，类型不符的字段被忽略
+// 并作为问题返回；只有 front matter 本身无法解析时才返回错误。
+func parseMarkdownFile(filePath string, loc *time.Location, termKeys []string) (*Article, []string, error) {
+	doc, err := frontmatter.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	article := &Article{
 		FilePath: filePath,
 	}
 
-	// 解析前置数据
-	var frontMatterContent string
-	var bodyContent string
-
-	if frontMatterStart >= 0 && frontMatterEnd > frontMatterStart {
-		// 提取前置数据
-		frontMatterLines := lines[frontMatterStart+1 : frontMatterEnd]
-		frontMatterContent = strings.Join(frontMatterLines, "\n")
-
-		// 定义前置数据结构体
-		type FrontMatter struct {
-			Title      string   `yaml:"title"`
-			Subtitle   string   `yaml:"subtitle"`
-			Summary    string   `yaml:"summary"`
-			Tags       []string `yaml:"tags"`
-			Categories []string `yaml:"categories"`
-			Date       string   `yaml:"date"`
-			LastMod    string   `yaml:"lastmod"`
-			Featured   bool     `yaml:"featured"`
-			Draft      bool     `yaml:"draft"`
-			Slug       string   `yaml:"slug"`
-		}
-
-		// 解析 YAML 前置数据
-		var frontMatter FrontMatter
-		if err := yaml.Unmarshal([]byte(frontMatterContent), &frontMatter); err != nil {
-			return nil, fmt.Errorf("YAML front matter 无效: %w", err)
-		}
-		article.Title = frontMatter.Title
-		article.Subtitle = frontMatter.Subtitle
-		article.Summary = frontMatter.Summary
-		article.Tags = frontMatter.Tags
-		article.Categories = frontMatter.Categories
-		article.Date = frontMatter.Date
-		article.LastMod = frontMatter.LastMod
-		article.Featured = frontMatter.Featured
-		article.Draft = frontMatter.Draft
-		article.Slug = frontMatter.Slug
-
-		// 提取正文内容
-		if frontMatterEnd+1 < len(lines) {
-			bodyLines := lines[frontMatterEnd+1:]
-			bodyContent = strings.Join(bodyLines, "\n")
-		}
-	} else {
-		// 没有前置数据，整个内容都是正文
-		bodyContent = contentStr
-	}
-
-	// 如果需要内容信息，则填充相关字段
-	if withContent {
-		// 构建前置信息
-		if frontMatterContent != "" {
-			article.FrontMatter = frontMatterContent
+	// 解析前置数据；YAML、TOML、JSON 三种格式统一解码为通用映射
+	var problems []string
+	if doc.Format != frontmatter.FormatNone {
+		decoded, err := doc.DecodeMap()
+		if err != nil {
+			return nil, nil, err
+		}
+		// Hugo 的 front matter 键不区分大小写，如 publishDate 与 publishdate
+		data := make(map[string]interface{}, len(decoded))
+		for key, value := range decoded {
+			data[strings.ToLower(key)] = value
 		}
 
-		// 解析正文为段落
-		article.BodyContent = splitTextIntoParagraphs(bodyContent)
+		stringField := func(key string) string {
+			value, ok := data[key]
+			if !ok || value == nil {
+				return ""
+			}
+			text, ok := scalarString(value)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("字段 %s 应为字符串，实际为 %T", key, value))
+			}
+			return text
+		}
+		boolField := func(key string) bool {
+			value, ok := data[key]
+			if !ok || value == nil {
+				return false
+			}
+			switch v := value.(type) {
+			case bool:
+				return v
+			case string:
+				if parsed, err := strconv.ParseBool(v); err == nil {
+					return parsed
+				}
+			}
+			problems = append(problems, fmt.Sprintf("字段 %s 应为布尔值，实际为 %v", key, value))
+			return false
+		}
+		dateField := func(keys ...string) time.Time {
+			for _, key := range keys {
+				value, ok := data[key]
+				if !ok || value == nil {
+					continue
+				}
+				date, err := parseDateValue(doc, key, value, loc)
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("字段 %s %v", key, err))
+				}
+				return date
+			}
+			return time.Time{}
+		}
+		listField := func(key string, extract func(interface{}) []string) []string {
+			value, ok := data[key]
+			if !ok || value == nil {
+				return nil
+			}
+			items := extract(value)
+			if items == nil {
+				problems = append(problems, fmt.Sprintf("字段 %s 应为字符串或字符串列表，实际为 %T", key, value))
+			}
+			return items
+		}
 
-		// 计算正文字符数
-		article.CharCount = len([]rune(bodyContent))
+		article.Title = stringField("title")
+		article.Subtitle = stringField("subtitle")
+		article.Summary = stringField("summary")
+		article.Tags = listField("tags", extractTagsFromYAML)
+		article.Categories = listField("categories", extractCategoriesFromYAML)
+		for _, key := range termKeys {
+			if terms := listField(key, extractTermsFromYAML); len(terms) > 0 {
+				if article.Terms == nil {
+					article.Terms = make(map[string][]string)
+				}
+				article.Terms[key] = terms
+			}
+		}
+		article.Date = dateField("date")
+		article.LastMod = dateField("lastmod", "modified")
+		article.PublishDate = dateField("publishdate", "pubdate", "published")
+		article.ExpiryDate = dateField("expirydate", "unpublishdate")
+		article.Featured = boolField("featured")
+		article.Draft = boolField("draft")
+		article.Slug = stringField("slug")
+		article.TranslationSourceHash = stringField(strings.ToLower(models.TranslationSourceHashKey))
 	}
+	article.SourceHash = SourceHash(doc)
 
-	return article, nil
-}
+	// 字符数随元数据一起缓存，统计翻译量时无需读取正文
+	article.CharCount = len([]rune(doc.Body))
 
-// splitTextIntoParagraphs 将文本分割成 Markdown 块。
-// 翻译阶段必须保留空行边界；否则列表、标题、普通段落会被合并成一个翻译请求，
-// 模型返回后容易生成 Hugo/Goldmark 无法正确解析的 Markdown。
-func splitTextIntoParagraphs(text string) []string {
-	var result []string
-	var current []string
-	inFence := false
-
-	flush := func() {
-		if len(current) == 0 {
-			return
-		}
-		block := strings.TrimSpace(strings.Join(current, "\n"))
-		if block != "" {
-			result = append(result, block)
-		}
-		current = nil
-	}
+	return article, problems, nil
+}
 
-	normalized := strings.ReplaceAll(text, "\r\n", "\n")
-	for _, line := range strings.Split(normalized, "\n") {
-		trimmed := strings.TrimSpace(line)
+// sourceHashExcluded 是由工具维护、不参与源文件哈希的字段，修改它们不会让译文过期
+var sourceHashExcluded = map[string]bool{
+	"slug":    true,
+	"aliases": true,
+	strings.ToLower(models.TranslationSourceHashKey): true,
+}
 
-		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
-			current = append(current, line)
-			inFence = !inFence
-			if !inFence {
-				flush()
+// SourceHash 计算源文件中需要翻译的内容的哈希，译文生成时记录在 translationSourceHash 中。
+// 只包含正文与 translate 策略字段的值；slug、aliases 以及复制或各语言自行维护的字段不参与，
+// 生成 slug、写入别名等修改不会让译文过期。换行风格、BOM、front matter 格式、字段顺序与
+// 正文首尾空白不影响结果。
+func SourceHash(doc *frontmatter.Document) string {
+	policies := config.GetGlobalConfig().FrontMatter
+	hash := sha256.New()
+	if data, err := doc.DecodeMap(); err == nil {
+		var keys []string
+		for key := range data {
+			if !sourceHashExcluded[strings.ToLower(key)] && policies.Policy(key) == config.FieldTranslate {
+				keys = append(keys, key)
 			}
-			continue
 		}
-
-		if inFence {
-			current = append(current, line)
-			continue
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, _ := json.Marshal(data[key])
+			hash.Write([]byte(key))
+			hash.Write([]byte{0})
+			hash.Write(value)
+			hash.Write([]byte{0})
 		}
+	}
+	hash.Write([]byte(strings.TrimSpace(doc.Body)))
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
 
-		if trimmed == "" {
-			flush()
-			continue
+// scalarString 把标量转为文本：日期按 Hugo 常见写法输出，数字和布尔值按字面形式输出。
+func scalarString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 && v.Location() == time.UTC {
+			return v.Format("2006-01-02"), true
 		}
-
-		current = append(current, line)
+		return v.Format(time.RFC3339), true
+	case int, int64, uint64, float64, bool:
+		return fmt.Sprint(v), true
+	default:
+		return "", false
 	}
-	flush()
-
-	return result
 }
 
-// extractTagsFromYAML 从 YAML 数据中提取标签
-func extractTagsFromYAML(tags interface{}) []string {
-	var result []string
-
-	switch v := tags.(type) {
-	case []interface{}:
-		for _, tag := range v {
-			if tagStr, ok := tag.(string); ok {
-				result = append(result, tagStr)
-			}
+// parseDateValue 把日期字段转换为 time.Time。YAML 取原文重新解析，TOML 的本地日期时间
+// 按站点时区解释，带偏移的日期保留原时区。
+func parseDateValue(doc *frontmatter.Document, key string, value interface{}, loc *time.Location) (time.Time, error) {
+	if raw, ok := doc.ScalarText(key); ok {
+		return models.ParseDate(raw, loc)
+	}
+	switch v := value.(type) {
+	case time.Time:
+		if strings.HasSuffix(v.Location().String(), "-local") {
+			return time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), loc), nil
 		}
+		return v, nil
 	case string:
-		// 处理单个字符串标签
-		result = append(result, v)
+		return models.ParseDate(v, loc)
+	default:
+		return time.Time{}, fmt.Errorf("应为日期，实际为 %T", value)
 	}
+}
 
-	return result
+// extractTagsFromYAML 从 YAML 数据中提取标签；单个标量视为只有一个标签，
+// 无法识别的类型返回 nil。
+func extractTagsFromYAML(tags interface{}) []string {
+	return extractTermsFromYAML(tags)
 }
 
 // extractCategoriesFromYAML 从 YAML 数据中提取分类
 func extractCategoriesFromYAML(categories interface{}) []string {
-	var result []string
+	return extractTermsFromYAML(categories)
+}
+
+func extractTermsFromYAML(value interface{}) []string {
+	result := []string{}
 
-	switch v := categories.(type) {
+	switch v := value.(type) {
 	case []interface{}:
-		for _, category := range v {
-			if categoryStr, ok := category.(string); ok {
-				result = append(result, categoryStr)
+		for _, item := range v {
+			if term, ok := scalarString(item); ok && strings.TrimSpace(term) != "" {
+				result = append(result, term)
+			}
+		}
+	case []string:
+		for _, term := range v {
+			if strings.TrimSpace(term) != "" {
+				result = append(result, term)
 			}
 		}
 	case string:
-		// 处理单个字符串分类
-		result = append(result, v)
+		// 处理单个字符串，如 tags: golang
+		if strings.TrimSpace(v) != "" {
+			result = append(result, v)
+		}
+	default:
+		if term, ok := scalarString(v); ok {
+			result = append(result, term)
+		} else {
+			return nil
+		}
 	}
 
 	return result
@@ -1,22 +1,95 @@
 package scanner
 
 import (
+	"hugo-content-suite/config"
+	"hugo-content-suite/frontmatter"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
-func TestScanArticlesReturnsFrontMatterError(t *testing.T) {
+func TestScanArticlesReportsFrontMatterErrorWithoutAborting(t *testing.T) {
 	dir := t.TempDir()
-	post := filepath.Join(dir, "bad")
-	if err := os.MkdirAll(post, 0o755); err != nil {
+	files := map[string]string{
+		filepath.Join("bad", "index.md"):  "---\ntitle: [\n---\nbody",
+		filepath.Join("good", "index.md"): "---\ntitle: 正常\n---\nbody",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	articles, issues, err := ScanArticlesWithIssues(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 1 || articles[0].Title != "正常" {
+		t.Fatalf("无效文件不应中断其他文件的扫描: %#v", articles)
+	}
+	if len(issues) != 1 || !issues[0].Skipped || issues[0].Path != filepath.Join(dir, "bad", "index.md") || issues[0].Reason == "" {
+		t.Fatalf("无效 front matter 应记录在问题列表中: %#v", issues)
+	}
+	if Skipped(issues) != 1 {
+		t.Fatalf("跳过的文件数为 %d", Skipped(issues))
+	}
+}
+
+func TestScanArticlesSkipsUnreadableDirectories(t *testing.T) {
+	dir := t.TempDir()
+	locked := filepath.Join(dir, "locked")
+	for _, name := range []string{filepath.Join("good", "index.md"), filepath.Join("locked", "index.md")} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("---\ntitle: 正常\n---\nbody"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(locked, 0o000); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(locked, 0o755)
+	if _, err := os.ReadDir(locked); err == nil {
+		t.Skip("当前用户不受目录权限限制")
+	}
+
+	articles, issues, err := ScanArticlesWithIssues(dir, false)
+	if err != nil {
+		t.Fatalf("单个目录不可读不应中断扫描: %v", err)
+	}
+	if len(articles) != 1 || articles[0].FilePath != filepath.Join(dir, "good", "index.md") {
+		t.Fatalf("articles=%#v", articles)
+	}
+	if len(issues) != 1 || !issues[0].Skipped || issues[0].Path != locked || issues[0].Reason == "" {
+		t.Fatalf("不可读的目录应记录在问题列表中: %#v", issues)
+	}
+}
+
+func TestScanArticlesAcceptsScalarTaxonomies(t *testing.T) {
+	dir := t.TempDir()
+	content := "---\ntitle: 标量\ntags: golang\ncategories: 编程\ndate: 2024-13-45\nfeatured: \"true\"\n---\nbody"
+	if err := os.WriteFile(filepath.Join(dir, "post.md"), []byte(content), 0o600); err != nil {
 		t.Fatal(err)
 	}
-	if err := os.WriteFile(filepath.Join(post, "index.md"), []byte("---\ntitle: [\n---\nbody"), 0o600); err != nil {
+	articles, issues, err := ScanArticlesWithIssues(dir, false)
+	if err != nil {
 		t.Fatal(err)
 	}
-	if _, err := ScanArticles(dir); err == nil {
-		t.Fatal("无效 front matter 应返回错误")
+	if len(articles) != 1 {
+		t.Fatalf("文章应被收录: %#v", articles)
+	}
+	article := articles[0]
+	if len(article.Tags) != 1 || article.Tags[0] != "golang" || len(article.Categories) != 1 || article.Categories[0] != "编程" || !article.Featured {
+		t.Fatalf("标量分类法解析错误: %#v", article)
+	}
+	if len(issues) != 1 || issues[0].Skipped {
+		t.Fatalf("无效日期应作为字段问题报告: %#v", issues)
 	}
 }
 
@@ -38,3 +111,197 @@ func TestScanArticlesKeepsFencedCodeBlock(t *testing.T) {
 		t.Fatalf("代码块边界丢失: %#v", articles)
 	}
 }
+
+func TestScanArticlesReadsTOMLAndJSONFrontMatter(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"toml": "+++\ntitle = \"TOML 文章\"\ntags = [\"go\"]\ndate = 2024-01-02T03:04:05+08:00\n+++\n正文",
+		"json": "{\n  \"title\": \"JSON 文章\",\n  \"tags\": [\"go\"]\n}\n正文",
+	}
+	for name, content := range files {
+		post := filepath.Join(dir, name)
+		if err := os.MkdirAll(post, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(post, "index.md"), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	articles, err := ScanArticles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 2 {
+		t.Fatalf("文章数量错误: %#v", articles)
+	}
+	for _, article := range articles {
+		if article.Title == "" || len(article.Tags) != 1 {
+			t.Fatalf("front matter 未解析: %#v", article)
+		}
+	}
+}
+
+func TestScanArticlesFindsSinglePagesAndSkipsBundleResources(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"_index.md":                    "---\ntitle: 栏目\n---\n",
+		"single.md":                    "---\ntitle: 单页\n---\n",
+		"single.en.md":                 "---\ntitle: Single\n---\n",
+		filepath.Join("b", "index.md"): "---\ntitle: 包\n---\n",
+		filepath.Join("b", "notes.md"): "---\ntitle: 资源\n---\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sources, err := ScanArticles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 3 {
+		t.Fatalf("应扫描到 _index.md、single.md 与 b/index.md: %#v", sources)
+	}
+	all, err := ScanArticlesWithLangs(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("全部语言应额外包含 single.en.md: %#v", all)
+	}
+}
+
+func TestScanArticlesParsesTypedDates(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"yaml.md": "---\ndate: 2024-01-02\npublishDate: 2024-01-03T08:00:00+08:00\nexpiryDate: 2025-01-01 12:00\n---\n",
+		"toml.md": "+++\ndate = 2024-01-02T10:00:00\nlastmod = 2024-02-01T00:00:00Z\n+++\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	articles, issues, err := ScanArticlesWithIssues(dir, false)
+	if err != nil || len(issues) != 0 || len(articles) != 2 {
+		t.Fatalf("articles=%#v issues=%#v err=%v", articles, issues, err)
+	}
+	for _, article := range articles {
+		switch filepath.Base(article.FilePath) {
+		case "yaml.md":
+			if article.Date.Year() != 2024 || article.Date.Location() == time.UTC || article.PublishDate.Hour() != 8 || article.ExpiryDate.IsZero() {
+				t.Fatalf("YAML 日期解析错误: %#v", article)
+			}
+		case "toml.md":
+			if article.Date.Hour() != 10 || article.Date.Location() == time.UTC || article.LastMod.Location() != time.UTC {
+				t.Fatalf("TOML 日期解析错误: %#v", article)
+			}
+		}
+	}
+}
+
+func TestScanArticlesReusesIndexForUnchangedFiles(t *testing.T) {
+	previous := config.GetGlobalConfig()
+	defer config.SetGlobalConfig(previous)
+	cfg := *previous
+	cfg.Paths.RuntimeDir = t.TempDir()
+	config.SetGlobalConfig(&cfg)
+
+	dir := t.TempDir()
+	unchanged := filepath.Join(dir, "a.md")
+	changed := filepath.Join(dir, "b.md")
+	for path, title := range map[string]string{unchanged: "甲", changed: "乙"} {
+		if err := os.WriteFile(path, []byte("---\ntitle: "+title+"\n---\n正文"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := ScanArticles(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.Paths.RuntimeDir, indexFileName)); err != nil {
+		t.Fatalf("索引应写入 runtime_dir: %v", err)
+	}
+
+	// 篡改未变文件的缓存标题：若再次扫描仍返回篡改值，说明没有重新解析。
+	key, _ := filepath.Abs(unchanged)
+	cachedIndex.Entries[key].Article.Title = "缓存"
+	if err := os.WriteFile(changed, []byte("---\ntitle: 乙改\n---\n更长的正文"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	articles, err := ScanArticlesForTranslation(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := map[string]string{}
+	for _, article := range articles {
+		titles[article.FilePath] = article.Title
+		if len(article.BodyContent) != 1 {
+			t.Fatalf("按需读取正文失败: %#v", article)
+		}
+	}
+	if titles[unchanged] != "缓存" || titles[changed] != "乙改" {
+		t.Fatalf("只应重新解析变更的文件: %v", titles)
+	}
+}
+
+func TestScanArticlesParsesConfiguredTaxonomies(t *testing.T) {
+	previous := config.GetGlobalConfig()
+	defer config.SetGlobalConfig(previous)
+
+	dir := t.TempDir()
+	content := "---\ntitle: 系列\nseries: 入门\nauthors: [张三, 李四]\n---\nbody"
+	if err := os.WriteFile(filepath.Join(dir, "post.md"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ScanArticles(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// 分类法变化后索引失效，未修改的文件也会重新解析出新分类法的词条
+	cfg := *previous
+	cfg.Taxonomies = map[string]string{"tag": "tags", "series": "series", "author": "authors"}
+	config.SetGlobalConfig(&cfg)
+	articles, err := ScanArticles(dir)
+	if err != nil || len(articles) != 1 {
+		t.Fatalf("articles=%#v err=%v", articles, err)
+	}
+	article := articles[0]
+	if series := article.TaxonomyTerms("series"); len(series) != 1 || series[0] != "入门" {
+		t.Fatalf("series 解析错误: %#v", article.Terms)
+	}
+	if authors := article.TaxonomyTerms("Authors"); len(authors) != 2 || authors[1] != "李四" {
+		t.Fatalf("authors 解析错误: %#v", article.Terms)
+	}
+}
+
+func TestSourceHashCoversBodyAndTranslatedFieldsOnly(t *testing.T) {
+	hash := func(content string) string {
+		doc, err := frontmatter.Parse(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return SourceHash(doc)
+	}
+	base := hash("---\ntitle: 标题\nslug: old\ndate: 2024-01-01\n---\n正文\n")
+	for name, content := range map[string]string{
+		"slug、aliases 与哈希": "---\ntitle: 标题\nslug: new\naliases: [old]\ntranslationSourceHash: abc\ndate: 2024-01-01\n---\n正文\n",
+		"复制策略的字段":          "---\ntitle: 标题\nslug: old\ndate: 2024-02-01\n---\n正文\n",
+		"TOML 格式":          "+++\ntitle = \"标题\"\nslug = \"old\"\n+++\n正文\n",
+	} {
+		if got := hash(content); got != base {
+			t.Errorf("%s不应改变源文件哈希", name)
+		}
+	}
+	for name, content := range map[string]string{
+		"标题": "---\ntitle: 新标题\nslug: old\ndate: 2024-01-01\n---\n正文\n",
+		"正文": "---\ntitle: 标题\nslug: old\ndate: 2024-01-01\n---\n新正文\n",
+	} {
+		if got := hash(content); got == base {
+			t.Errorf("%s变化应改变源文件哈希", name)
+		}
+	}
+}
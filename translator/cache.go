@@ -22,6 +22,7 @@ type CacheEntry struct {
 	Translation string    `json:"translation"`
 	Timestamp   time.Time `json:"timestamp"`
 	Type        CacheType `json:"type"`
+	Pinned      bool      `json:"pinned,omitempty"` // 人工选定的结果，生成时优先于模型与规范整理
 }
 
 type TranslationCache struct {
@@ -31,6 +32,9 @@ type TranslationCache struct {
 	tagCache          map[string]CacheEntry
 	slugCache         map[string]CacheEntry
 	categoryCache     map[string]CacheEntry // 新增
+	// 其他分类法的词条缓存，按命名空间首次使用时加载
+	cfg        *config.Config
+	termCaches map[CacheType]map[string]CacheEntry
 }
 
 func NewTranslationCache() *TranslationCache {
@@ -45,9 +49,29 @@ func NewTranslationCacheWithConfig(cfg *config.Config) *TranslationCache {
 		tagCache:          make(map[string]CacheEntry),
 		slugCache:         make(map[string]CacheEntry),
 		categoryCache:     make(map[string]CacheEntry), // 新增
+		cfg:               cfg,
+		termCaches:        make(map[CacheType]map[string]CacheEntry),
 	}
 }
 
+// termCache 返回其他分类法命名空间的缓存，文件由 config.TermCacheFile 决定
+func (c *TranslationCache) termCache(cacheType CacheType) map[string]CacheEntry {
+	if cache, ok := c.termCaches[cacheType]; ok {
+		return cache
+	}
+	cache := make(map[string]CacheEntry)
+	file := c.cfg.TermCacheFile(string(cacheType))
+	if err := c.loadCacheFile(file, &cache); err != nil {
+		utils.WarnWithFields("加载词条缓存失败", map[string]interface{}{
+			"file":  file,
+			"error": err.Error(),
+		})
+		cache = make(map[string]CacheEntry)
+	}
+	c.termCaches[cacheType] = cache
+	return cache
+}
+
 func (c *TranslationCache) Load() error {
 	// 加载标签缓存
 	if err := c.loadCacheFile(c.tagCacheFile, &c.tagCache); err != nil {
@@ -120,6 +144,12 @@ func (c *TranslationCache) Save() error {
 		return fmt.Errorf("保存分类缓存失败: %v", err)
 	}
 
+	for cacheType, cache := range c.termCaches {
+		if err := c.saveCacheFile(c.cfg.TermCacheFile(string(cacheType)), cache); err != nil {
+			return fmt.Errorf("保存 %s 词条缓存失败: %v", cacheType, err)
+		}
+	}
+
 	fmt.Printf("💾 已保存缓存文件 - 标签: %d 个, 文章: %d 个, 分类: %d 个\n",
 		len(c.tagCache), len(c.slugCache), len(c.categoryCache))
 	return nil
@@ -157,26 +187,49 @@ func (c *TranslationCache) saveCacheFile(filename string, cache map[string]Cache
 }
 
 func (c *TranslationCache) Get(text string, cacheType CacheType) (string, bool) {
-	var cache map[string]CacheEntry
-	switch cacheType {
-	case kTagCache:
-		cache = c.tagCache
-	case kSlugCache:
-		cache = c.slugCache
-	case kCategoryCache:
-		cache = c.categoryCache
-	default:
+	entry, exists := c.entries(cacheType)[text]
+	if !exists {
 		return "", false
 	}
 
-	entry, exists := cache[text]
-	if !exists {
-		return "", false
+	return entry.Translation, true
+}
+
+// Pin 写入人工选定的结果并标记为固定
+func (c *TranslationCache) Pin(text, translation string, cacheType CacheType) {
+	c.Set(text, translation, cacheType)
+	if cache := c.entries(cacheType); cache != nil {
+		entry := cache[text]
+		entry.Pinned = true
+		cache[text] = entry
 	}
+}
 
+// GetPinned 返回固定的结果，没有缓存或未固定时 ok 为 false
+func (c *TranslationCache) GetPinned(text string, cacheType CacheType) (string, bool) {
+	entry, ok := c.entries(cacheType)[text]
+	if !ok || !entry.Pinned {
+		return "", false
+	}
 	return entry.Translation, true
 }
 
+// entries 返回 cacheType 对应的缓存，类型为空时返回 nil
+func (c *TranslationCache) entries(cacheType CacheType) map[string]CacheEntry {
+	switch cacheType {
+	case kTagCache:
+		return c.tagCache
+	case kSlugCache:
+		return c.slugCache
+	case kCategoryCache:
+		return c.categoryCache
+	case "":
+		return nil
+	default:
+		return c.termCache(cacheType)
+	}
+}
+
 func (c *TranslationCache) Set(text, translation string, cacheType CacheType) {
 	entry := CacheEntry{
 		Translation: translation,
@@ -184,13 +237,8 @@ func (c *TranslationCache) Set(text, translation string, cacheType CacheType) {
 		Type:        cacheType,
 	}
 
-	switch cacheType {
-	case kTagCache:
-		c.tagCache[text] = entry
-	case kSlugCache:
-		c.slugCache[text] = entry
-	case kCategoryCache:
-		c.categoryCache[text] = entry
+	if cache := c.entries(cacheType); cache != nil {
+		cache[text] = entry
 	}
 }
 
@@ -219,19 +267,7 @@ func (c *TranslationCache) GetCachedTranslations(texts []string, targetLang stri
 }
 
 func (c *TranslationCache) GetStats(cacheType CacheType) (total int) {
-	var cache map[string]CacheEntry
-	switch cacheType {
-	case kTagCache:
-		cache = c.tagCache
-	case kSlugCache:
-		cache = c.slugCache
-	case kCategoryCache:
-		cache = c.categoryCache
-	default:
-		return 0
-	}
-
-	return len(cache)
+	return len(c.entries(cacheType))
 }
 
 func (c *TranslationCache) Clear(cacheType CacheType) error {
@@ -245,8 +281,11 @@ func (c *TranslationCache) Clear(cacheType CacheType) error {
 	case kCategoryCache:
 		c.categoryCache = make(map[string]CacheEntry)
 		return c.saveCacheFile(c.categoryCacheFile, c.categoryCache)
-	default:
+	case "":
 		return fmt.Errorf("未知的缓存类型: %v", cacheType)
+	default:
+		c.termCaches[cacheType] = make(map[string]CacheEntry)
+		return c.saveCacheFile(c.cfg.TermCacheFile(string(cacheType)), c.termCaches[cacheType])
 	}
 }
 
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"hugo-content-suite/config"
+	"hugo-content-suite/utils"
 	"io"
 	"net/http"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -117,15 +119,109 @@ func (t *TranslationUtils) TranslateTags(texts []string) (map[string]string, err
 	return t.batchTranslateWithCache(texts, "en", kTagCache)
 }
 
+// TranslateTerms 把分类法词条批量翻译为英文，使用分类法的缓存命名空间
+func (t *TranslationUtils) TranslateTerms(texts []string, cache string) (map[string]string, error) {
+	return t.batchTranslateWithCache(texts, "en", CacheType(cache))
+}
+
 func (t *TranslationUtils) TranslateArticlesSlugs(texts []string) (map[string]string, error) {
 	return t.batchTranslateWithCache(texts, "en", kSlugCache)
 }
 
+// SuggestArticleSlugs 请模型为文章标题给出 count 个不同的英文 slug 候选，结果不写入缓存
+func (t *TranslationUtils) SuggestArticleSlugs(title string, count int) ([]string, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	system := "你是技术博客的编辑，负责为文章拟定简短、准确的英文 URL slug。仅输出 slug。"
+	request := LMStudioRequest{
+		Model: t.llm.Model,
+		Messages: []Message{
+			{Role: "system", Content: system},
+			{Role: "user", Content: fmt.Sprintf("为下面的文章标题给出 %d 个不同的英文 URL slug，只使用小写字母、数字和连字符，每行一个，不要编号和解释。\n\n标题：%s", count, title)},
+		},
+		Stream:      false,
+		Temperature: 0.7, // 候选需要有差异，不使用翻译时的 0.0
+		TopP:        1.0,
+		MaxTokens:   1000,
+	}
+	response, err := t.sendRequest(request, system)
+	if err != nil {
+		return nil, err
+	}
+	return parseSlugCandidates(response, count), nil
+}
+
+// parseSlugCandidates 从模型回复中取出最多 count 个不重复的候选，去掉思考内容、编号与引号
+func parseSlugCandidates(response string, count int) []string {
+	response = regexp.MustCompile(`(?s)<think>.*?</think>`).ReplaceAllString(response, "")
+	marker := regexp.MustCompile(`^(?:[-*•]|\d+[.)、])\s*`)
+	var candidates []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.Trim(marker.ReplaceAllString(strings.TrimSpace(line), ""), " `'\"")
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		candidates = append(candidates, line)
+		if len(candidates) == count {
+			break
+		}
+	}
+	return candidates
+}
+
+// PinArticleSlug 把人工选定的 slug 固定到文章 slug 缓存，之后生成 path 处文章的 slug 时直接使用。
+// 固定按文章文件记录，不同栏目中标题相同的文章互不影响，也不改变按标题缓存的翻译结果
+func (t *TranslationUtils) PinArticleSlug(path, slug string) error {
+	t.cache.Pin(pinKey(path), slug, kSlugCache)
+	return t.cache.Save()
+}
+
+// PinnedArticleSlugs 返回 paths 中 slug 已固定的文章文件 → slug
+func (t *TranslationUtils) PinnedArticleSlugs(paths []string) map[string]string {
+	pinned := make(map[string]string)
+	for _, path := range paths {
+		if slug, ok := t.cache.GetPinned(pinKey(path), kSlugCache); ok {
+			pinned[path] = slug
+		}
+	}
+	return pinned
+}
+
+// pinKey 返回文章固定 slug 的缓存键：pin: 加文件的绝对路径，与 语言:原文 形式的翻译条目区分
+func pinKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return "pin:" + path
+}
+
 func (t *TranslationUtils) TranslateToLanguage(content, targetLang string) (string, error) {
-	result, err := t.translateWithAPI(content, targetLang)
+	return t.translateValidated(content, targetLang, "")
+}
+
+// TranslateField 翻译前置数据字段：cache 为分类法的缓存命名空间（如 tag、category）时使用对应缓存，
+// promptTemplate 非空时替代语言的用户消息模板。
+func (t *TranslationUtils) TranslateField(content, targetLang, cache, promptTemplate string) (string, error) {
+	if cache == "" || cache == config.FieldCacheNone {
+		return t.translateValidated(content, targetLang, promptTemplate)
+	}
+	return t.cachedTranslate(content, targetLang, CacheType(cache), promptTemplate)
+}
+
+func (t *TranslationUtils) translateValidated(content, targetLang, promptTemplate string) (string, error) {
+	result, err := t.translateWithPrompt(content, targetLang, promptTemplate)
 	if err != nil {
 		return "", err
 	}
+	if t.cfg.Translation.ValidateResult {
+		// 原文含汉字而译文完全没有目标书写系统的字符，通常是模型原样返回或译错了语言。
+		if spec, _ := t.cfg.Language.Spec(targetLang); utils.ContainsChinese(content) && !spec.MatchesScript(result) {
+			return "", fmt.Errorf("译文不符合 %s 的书写系统 %s", spec.Name, spec.Script)
+		}
+	}
 	return result, nil
 }
 
@@ -146,6 +242,10 @@ func (t *TranslationUtils) TranslateTag(content, targetLang string) (string, err
 }
 
 func (t *TranslationUtils) translateWithCache(text, targetLang string, cacheType CacheType) (string, error) {
+	return t.cachedTranslate(text, targetLang, cacheType, "")
+}
+
+func (t *TranslationUtils) cachedTranslate(text, targetLang string, cacheType CacheType, promptTemplate string) (string, error) {
 	fmt.Println("\n🤖 使用AI翻译...")
 	cacheKey := fmt.Sprintf("%s:%s", targetLang, text)
 	if cached, found := t.cache.Get(cacheKey, cacheType); found {
@@ -154,7 +254,7 @@ func (t *TranslationUtils) translateWithCache(text, targetLang string, cacheType
 	}
 
 	fmt.Printf("🚀 [API Translate] [%s] %s\n", targetLang, text)
-	translated, err := t.translateWithAPI(text, targetLang)
+	translated, err := t.translateWithPrompt(text, targetLang, promptTemplate)
 	if err != nil {
 		fmt.Printf("❌ [API Error] [%s] %s: %v\n", targetLang, text, err)
 		return "", err
@@ -290,11 +390,12 @@ func (t *TranslationUtils) sendAnthropicRequest(request LMStudioRequest, system
 }
 
 func (t *TranslationUtils) translateWithAPI(content, targetLang string) (string, error) {
-	targetLangName := t.cfg.Language.LanguageNames[targetLang]
+	return t.translateWithPrompt(content, targetLang, "")
+}
 
-	if targetLangName == "" {
-		targetLangName = targetLang
-	}
+// translateWithPrompt 调用模型翻译；promptTemplate 为空时使用语言的模板，示例与请求使用同一模板
+func (t *TranslationUtils) translateWithPrompt(content, targetLang, promptTemplate string) (string, error) {
+	spec, _ := t.cfg.Language.Spec(targetLang)
 
 	systemContent := `
 	忽略以前设置的所有指令。
@@ -318,68 +419,18 @@ func (t *TranslationUtils) translateWithAPI(content, targetLang string) (string,
 		Content: systemContent,
 	})
 
-	// 添加历史翻译示例
-	switch targetLang {
-	case "en":
-		messages = append(messages,
-			Message{Role: "user", Content: "请将以下内容翻译为 English: 人工智能"},
-			Message{Role: "assistant", Content: "Artificial Intelligence"},
-			Message{Role: "user", Content: "请将以下内容翻译为 English: 机器学习"},
-			Message{Role: "assistant", Content: "Machine Learning"},
-			Message{Role: "user", Content: "请将以下内容翻译为 English: - 数据挖掘\n- 深度学习\n- 神经网络"},
-			Message{Role: "assistant", Content: "- Data Mining\n- Deep Learning\n- Neural Network"},
-		)
-	case "ja":
-		messages = append(messages,
-			Message{Role: "user", Content: "请将以下内容翻译为 Japanese: 人工智能"},
-			Message{Role: "assistant", Content: "人工知能"},
-			Message{Role: "user", Content: "请将以下内容翻译为 Japanese: 机器学习"},
-			Message{Role: "assistant", Content: "機械学習"},
-			Message{Role: "user", Content: "请将以下内容翻译为 Japanese: - 数据挖掘\n- 深度学习\n- 神经网络"},
-			Message{Role: "assistant", Content: "- データマイニング\n- ディープラーニング\n- ニューラルネットワーク"},
-		)
-	case "ko":
-		messages = append(messages,
-			Message{Role: "user", Content: "请将以下内容翻译为 Korean: 人工智能"},
-			Message{Role: "assistant", Content: "인공지능"},
-			Message{Role: "user", Content: "请将以下内容翻译为 Korean: 机器学习"},
-			Message{Role: "assistant", Content: "기계학습"},
-			Message{Role: "user", Content: "请将以下内容翻译为 Korean: - 数据挖掘\n- 深度学习\n- 神经网络"},
-			Message{Role: "assistant", Content: "- 데이터 마이닝\n- 딥러닝\n- 신경망"},
-		)
-	case "fr":
-		messages = append(messages,
-			Message{Role: "user", Content: "请将以下内容翻译为 French: 人工智能"},
-			Message{Role: "assistant", Content: "Intelligence Artificielle"},
-			Message{Role: "user", Content: "请将以下内容翻译为 French: 机器学习"},
-			Message{Role: "assistant", Content: "Apprentissage Automatique"},
-			Message{Role: "user", Content: "请将以下内容翻译为 French: - 数据挖掘\n- 深度学习\n- 神经网络"},
-			Message{Role: "assistant", Content: "- Exploration de Données\n- Apprentissage Profond\n- Réseau de Neurones"},
-		)
-	case "ru":
-		messages = append(messages,
-			Message{Role: "user", Content: "请将以下内容翻译为 Russian: 人工智能"},
-			Message{Role: "assistant", Content: "Искусственный интеллект"},
-			Message{Role: "user", Content: "请将以下内容翻译为 Russian: 机器学习"},
-			Message{Role: "assistant", Content: "Машинное обучение"},
-			Message{Role: "user", Content: "请将以下内容翻译为 Russian: - 数据挖掘\n- 深度学习\n- 神经网络"},
-			Message{Role: "assistant", Content: "- Интеллектуальный анализ данных\n- Глубокое обучение\n- Нейронная сеть"},
-		)
-	case "hi":
+	// 添加历史翻译示例，示例来自语言注册表
+	for _, example := range spec.Examples {
 		messages = append(messages,
-			Message{Role: "user", Content: "请将以下内容翻译为 Hindi: 人工智能"},
-			Message{Role: "assistant", Content: "कृत्रिम बुद्धिमत्ता"},
-			Message{Role: "user", Content: "请将以下内容翻译为 Hindi: 机器学习"},
-			Message{Role: "assistant", Content: "मशीन लर्निंग"},
-			Message{Role: "user", Content: "请将以下内容翻译为 Hindi: - 数据挖掘\n- 深度学习\n- 神经网络"},
-			Message{Role: "assistant", Content: "- डेटा माइनिंग\n- डीप लर्निंग\n- न्यूरल नेटवर्क"},
+			Message{Role: "user", Content: spec.PromptWith(promptTemplate, example.Source)},
+			Message{Role: "assistant", Content: example.Target},
 		)
 	}
 
 	// 添加当前翻译请求
 	messages = append(messages, Message{
 		Role:    "user",
-		Content: fmt.Sprintf("请将以下内容翻译为 %s: %s", targetLangName, content),
+		Content: spec.PromptWith(promptTemplate, content),
 	})
 
 	request := LMStudioRequest{
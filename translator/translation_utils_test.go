@@ -1,9 +1,11 @@
 package translator
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"hugo-content-suite/config"
@@ -69,3 +71,82 @@ func TestAnthropicRequestUsesAPIKeyEnvAndHeaders(t *testing.T) {
 		t.Fatalf("Anthropic 翻译=%q, err=%v", got, err)
 	}
 }
+
+func TestTranslateFieldUsesFieldPromptAndCache(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request LMStudioRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		prompts = append(prompts, request.Messages[len(request.Messages)-1].Content)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"Caption"}}]}`))
+	}))
+	defer server.Close()
+	translator := NewTranslationUtilsWithConfig(testConfig(t.TempDir(), server.URL), server.Client())
+
+	for i := 0; i < 2; i++ {
+		got, err := translator.TranslateField("图注", "en", config.FieldCacheTag, "图注译为 {language}: {content}")
+		if err != nil || got != "Caption" {
+			t.Fatalf("翻译结果=%q, err=%v", got, err)
+		}
+	}
+	if len(prompts) != 1 || prompts[0] != "图注译为 English: 图注" {
+		t.Fatalf("prompts=%q", prompts)
+	}
+}
+
+func TestTranslateFieldUsesTaxonomyCacheNamespace(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"Getting Started"}}]}`))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	cfg := testConfig(dir, server.URL)
+	cfg.Paths.RuntimeDir = dir
+
+	if got, err := NewTranslationUtilsWithConfig(cfg, server.Client()).TranslateField("入门", "en", "series", ""); err != nil || got != "Getting Started" {
+		t.Fatalf("翻译结果=%q, err=%v", got, err)
+	}
+	// 新实例从 series 的缓存文件读取，不再请求模型，也不会写入标签缓存
+	if got, err := NewTranslationUtilsWithConfig(cfg, server.Client()).TranslateField("入门", "en", "series", ""); err != nil || got != "Getting Started" || calls != 1 {
+		t.Fatalf("翻译结果=%q, err=%v, calls=%d", got, err, calls)
+	}
+	if got, err := NewTranslationUtilsWithConfig(cfg, server.Client()).TranslateField("入门", "en", config.FieldCacheTag, ""); err != nil || got != "Getting Started" || calls != 2 {
+		t.Fatalf("不同命名空间不应共用缓存: calls=%d err=%v", calls, err)
+	}
+}
+
+func TestSuggestedSlugsAreParsedAndPinnedSlugsSurviveReload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"<think>想一想</think>\n1. go-scheduler-guide\n- ` + "`go-scheduler`" + `\n\n1. go-scheduler-guide\n\"inside-go-scheduler\"\nextra"}}]}`))
+	}))
+	defer server.Close()
+	cfg := testConfig(t.TempDir(), server.URL)
+	translator := NewTranslationUtilsWithConfig(cfg, server.Client())
+
+	got, err := translator.SuggestArticleSlugs("Go 调度器", 3)
+	if err != nil || strings.Join(got, ",") != "go-scheduler-guide,go-scheduler,inside-go-scheduler" {
+		t.Fatalf("候选=%q, err=%v", got, err)
+	}
+	if _, found := translator.cache.Get("en:Go 调度器", kSlugCache); found {
+		t.Fatal("候选不得写入缓存")
+	}
+
+	// 两个栏目中标题相同的文章，只固定其中一篇
+	post := filepath.Join("content", "post", "go-scheduler", "index.md")
+	notes := filepath.Join("content", "notes", "go-scheduler", "index.md")
+	if err := translator.PinArticleSlug(post, "go-scheduler"); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := NewTranslationUtilsWithConfig(cfg, server.Client())
+	if pinned := reloaded.PinnedArticleSlugs([]string{post, notes}); len(pinned) != 1 || pinned[post] != "go-scheduler" {
+		t.Fatalf("固定的 slug=%v", pinned)
+	}
+	if _, found := reloaded.cache.Get("en:Go 调度器", kSlugCache); found {
+		t.Fatal("固定的 slug 不应成为按标题的翻译缓存")
+	}
+}
@@ -119,28 +119,3 @@ func ReadFileContent(filePath string) (string, error) {
 	}
 	return string(content), nil
 }
-
-// BuildTargetFilePath 根据语言构建目标文件路径
-func BuildTargetFilePath(originalPath, targetLang string) string {
-	dir := filepath.Dir(originalPath)
-	baseName := filepath.Base(originalPath)
-
-	if !strings.HasSuffix(baseName, ".md") {
-		return ""
-	}
-
-	switch targetLang {
-	case "ja":
-		return filepath.Join(dir, "index.ja.md")
-	case "ko":
-		return filepath.Join(dir, "index.ko.md")
-	case "fr":
-		return filepath.Join(dir, "index.fr.md")
-	case "ru":
-		return filepath.Join(dir, "index.ru.md")
-	case "hi":
-		return filepath.Join(dir, "index.hi.md")
-	default: // "en" 或其他
-		return filepath.Join(dir, "index.en.md")
-	}
-}
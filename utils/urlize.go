@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Urlize 按 Hugo urlize 的规则生成路径：转为小写，保留字母、数字、组合符号与 . / \ _ # + ~ - @，
+// 空白转为单个连字符，其余字符删除。Hugo 会对结果再做 URL 转义，这里返回转义前的形式，
+// 与 Hugo 在内容目录中查找词条页面时使用的路径一致。
+func Urlize(s string) string {
+	source := []rune(strings.ToLower(s))
+	target := make([]rune, 0, len(source))
+	prependHyphen, wasHyphen := false, false
+	for i, r := range source {
+		allowed := r == '.' || r == '/' || r == '\\' || r == '_' || r == '#' || r == '+' || r == '~' || r == '-' || r == '@' ||
+			unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) ||
+			(r == '%' && i+2 < len(source) && isHex(source[i+1]) && isHex(source[i+2]))
+		if allowed {
+			if prependHyphen {
+				if !wasHyphen {
+					target = append(target, '-')
+				}
+				prependHyphen = false
+			}
+			target = append(target, r)
+			wasHyphen = r == '-'
+		} else if len(target) > 0 && unicode.IsSpace(r) {
+			prependHyphen = true
+		}
+	}
+	return string(target)
+}
+
+func isHex(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+// TermDirName 返回词条页面的目录名，即 Hugo 查找词条内容时使用的 urlize 结果。
+// 结果不一定能作为单层目录使用，写入前用 TermDirProblem 检查
+func TermDirName(term string) string {
+	return Urlize(term)
+}
+
+// TermDirProblem 返回目录名 name 不能用作词条页面目录的原因，可以使用时为空串：
+// 为空、包含路径分隔符（Hugo 按含分隔符的名称查找，写成其他目录会成为孤立页面）、
+// 以点开头（隐藏目录）、以点结尾或是 Windows 保留名
+func TermDirProblem(name string) string {
+	switch {
+	case name == "":
+		return "无法生成目录名"
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Sprintf("目录名 %q 包含路径分隔符", name)
+	case strings.HasPrefix(name, "."):
+		return fmt.Sprintf("目录名 %q 以点开头，是隐藏目录", name)
+	case strings.HasSuffix(name, "."):
+		return fmt.Sprintf("目录名 %q 以点结尾，在 Windows 上无效", name)
+	case ReservedOnWindows(name):
+		return fmt.Sprintf("目录名 %q 是 Windows 保留名", name)
+	}
+	return ""
+}
+
+// ReservedOnWindows 判断目录名在 Windows 上是否为保留的设备名，如 con、nul、com1
+func ReservedOnWindows(name string) bool {
+	base := strings.ToLower(name)
+	if i := strings.IndexByte(base, '.'); i >= 0 {
+		base = base[:i]
+	}
+	switch base {
+	case "con", "prn", "aux", "nul":
+		return true
+	}
+	if len(base) == 4 && (strings.HasPrefix(base, "com") || strings.HasPrefix(base, "lpt")) {
+		return base[3] >= '1' && base[3] <= '9'
+	}
+	return false
+}